@@ -10,6 +10,7 @@ import (
 
 const (
 	// ControlSets
+	APR = 0x0D
 	LS1 = 0x0E
 	LS0 = 0x0F
 	SS2 = 0x19
@@ -66,9 +67,10 @@ type (
 		gr GraphicSetElement
 	}
 
+	// NOTE set and singleSet are positions of graphic set. (G0, G1, G2 or G3)
 	GraphicSetElement struct {
-		set       *byte
-		singleSet *byte
+		set       byte
+		singleSet byte
 		Single    bool
 	}
 )
@@ -104,8 +106,8 @@ func NewEBitCharacterDecorder() (decorder EBitCharacterDecorder) {
 		g2: HIRAGANA,
 		g3: KATAKANA,
 	}
-	decorder.gl.set = &(decorder.g0)
-	decorder.gr.set = &(decorder.g2)
+	decorder.gl.set = G0
+	decorder.gr.set = G2
 	return decorder
 }
 
@@ -121,8 +123,15 @@ func (decorder EBitCharacterDecorder) Decode(buffer []byte) string {
 		word := buffer[idx]
 		switch getWordType(word) {
 		case WORD_CONTROL:
+			if APR == word {
+				decodedStr += "\n"
+			}
 			readSize := decorder.control(buffer[idx:])
 			idx += (readSize - 1)
+		case WORD_SPECIAL_SYMBOL:
+			if SP == word {
+				decodedStr += " "
+			}
 		case WORD_GL_SYMBOL:
 			str, readByte := decorder.decodeGl(buffer[idx:])
 			idx += (readByte - 1)
@@ -141,22 +150,20 @@ func (decorder *EBitCharacterDecorder) control(buffer []byte) int {
 	word := buffer[0]
 	readByte := 1
 	switch word {
-	case LS1:
-		decorder.gl.set = &(decorder.g0)
-		decorder.gl.Single = false
 	case LS0:
-		decorder.gl.set = &(decorder.g1)
+		decorder.gl.set = G0
+		decorder.gl.Single = false
+	case LS1:
+		decorder.gl.set = G1
 		decorder.gl.Single = false
 	case SS2:
-		decorder.gl.singleSet = &(decorder.g2)
+		decorder.gl.singleSet = G2
 		decorder.gl.Single = true
 	case ESC:
 		readByte += decorder.escControl(word, buffer)
 	case SS3:
-		decorder.gl.set = &(decorder.g3)
+		decorder.gl.singleSet = G3
 		decorder.gl.Single = true
-	case SP:
-		// T.B.D.
 	default:
 		// T.B.D.
 	}
@@ -164,27 +171,34 @@ func (decorder *EBitCharacterDecorder) control(buffer []byte) int {
 	return readByte
 }
 
+// NOTE truncated escape sequence is skipped to the end of buffer.
 func (decorder *EBitCharacterDecorder) escControl(word byte, buffer []byte) int {
+	if len(buffer) < 2 {
+		return 0
+	}
 	nextWord := buffer[1]
 	readByte := 1 // read size in this function
 	switch nextWord {
 	case LS2:
-		decorder.gl.set = &(decorder.g2)
+		decorder.gl.set = G2
 		decorder.gl.Single = false
 	case LS3:
-		decorder.gl.set = &(decorder.g3)
+		decorder.gl.set = G3
 		decorder.gl.Single = false
 	case LS1R:
-		decorder.gr.set = &(decorder.g1)
+		decorder.gr.set = G1
 		decorder.gr.Single = false
 	case LS2R:
-		decorder.gr.set = &(decorder.g2)
+		decorder.gr.set = G2
 		decorder.gr.Single = false
 	case LS3R:
-		decorder.gr.set = &(decorder.g3)
+		decorder.gr.set = G3
 		decorder.gr.Single = false
 	// Graphic Set
 	case G0, G1, G2, G3:
+		if len(buffer) < 3 {
+			return len(buffer) - 1
+		}
 		thirdByte := buffer[2]
 		_, ok := finalBytes[thirdByte]
 		if ok {
@@ -195,12 +209,18 @@ func (decorder *EBitCharacterDecorder) escControl(word byte, buffer []byte) int
 			readByte = 3
 		}
 	case DBYTE:
+		if len(buffer) < 3 {
+			return len(buffer) - 1
+		}
 		thirdByte := buffer[2]
 		_, ok := finalBytes[thirdByte]
 		if ok {
 			decorder.setGraphicSet(G0, thirdByte)
 			readByte = 2
-		} else if thirdByte == G1 || thirdByte == G2 || thirdByte == G3 {
+		} else if thirdByte == G0 || thirdByte == G1 || thirdByte == G2 || thirdByte == G3 {
+			if len(buffer) < 4 {
+				return len(buffer) - 1
+			}
 			decorder.setGraphicSet(thirdByte, buffer[3])
 			readByte = 3
 		} else if DRCS == thirdByte {
@@ -226,22 +246,47 @@ func (decorder *EBitCharacterDecorder) setGraphicSet(positionByte byte, finalByt
 	}
 }
 
+// Returns final byte of graphic set which is designated to position.
+func (decorder *EBitCharacterDecorder) graphicSet(positionByte byte) byte {
+	switch positionByte {
+	case G0:
+		return decorder.g0
+	case G1:
+		return decorder.g1
+	case G2:
+		return decorder.g2
+	case G3:
+		return decorder.g3
+	}
+	return 0
+}
+
 func (decorder *EBitCharacterDecorder) decodeGl(buffer []byte) (string, int) {
 	if WORD_GL_SYMBOL != getWordType(buffer[0]) {
 		panic("Not GL buffer.")
 	}
 
+	// single shift affects only one character.
+	if decorder.gl.Single {
+		decorder.gl.Single = false
+		finalByte := decorder.graphicSet(decorder.gl.singleSet)
+		size := characterSize(finalByte)
+		if len(buffer) < size {
+			return "", len(buffer)
+		}
+		return decorder.decode(finalByte, buffer[:size]), size
+	}
+
 	// check gl area
 	for idx, v := range buffer {
 		switch getWordType(v) {
 		case WORD_CONTROL, WORD_GR_SYMBOL:
-			return decorder.decode(*decorder.gl.set, buffer[:idx]), idx
+			return decorder.decode(decorder.graphicSet(decorder.gl.set), buffer[:idx]), idx
 		case WORD_SPECIAL_SYMBOL:
-			// T.B.D.
-			return decorder.decode(*decorder.gl.set, buffer[:idx]), idx
+			return decorder.decode(decorder.graphicSet(decorder.gl.set), buffer[:idx]), idx
 		}
 	}
-	return decorder.decode(*decorder.gl.set, buffer), len(buffer)
+	return decorder.decode(decorder.graphicSet(decorder.gl.set), buffer), len(buffer)
 }
 
 func (decorder *EBitCharacterDecorder) decodeGr(buffer []byte) (string, int) {
@@ -254,14 +299,13 @@ func (decorder *EBitCharacterDecorder) decodeGr(buffer []byte) (string, int) {
 	for idx, v := range buffer {
 		switch getWordType(v) {
 		case WORD_CONTROL, WORD_GL_SYMBOL:
-			return decorder.decode(*decorder.gr.set, grBuffer), idx
+			return decorder.decode(decorder.graphicSet(decorder.gr.set), grBuffer), idx
 		case WORD_SPECIAL_SYMBOL:
-			// T.B.D.
-			return decorder.decode(*decorder.gr.set, grBuffer), idx
+			return decorder.decode(decorder.graphicSet(decorder.gr.set), grBuffer), idx
 		}
 		grBuffer = append(grBuffer, v&0x7F)
 	}
-	return decorder.decode(*decorder.gr.set, grBuffer), len(grBuffer)
+	return decorder.decode(decorder.graphicSet(decorder.gr.set), grBuffer), len(grBuffer)
 }
 
 func (decorder *EBitCharacterDecorder) decode(decodeType byte, buffer []byte) string {
@@ -288,24 +332,44 @@ func decodeKanji(buffer []byte) string {
 // NOTE adjust to ISO-2022-JP's HIRAGANA code
 // JIS 0x24
 func decodeHiragana(buffer []byte) string {
-	jisBuffer := make([]byte, len(buffer)*2)
-	for idx, word := range buffer {
-		jisBuffer[idx*2] = byte(0x24)
-		jisBuffer[idx*2+1] = word
-	}
-	return decodeKanji(append(ESC_KANJI, jisBuffer...))
+	return decodeKana(buffer, 0x24, []rune("ゝゞー。「」、・"))
 }
 
 // NOTE adjust to ISO-2022-JP's KATAKANA code
 // JIS 0x25
 func decodeKatakana(buffer []byte) string {
-	jisBuffer := make([]byte, len(buffer)*2)
-	for idx, word := range buffer {
-		jisBuffer[idx*2] = byte(0x25)
-		jisBuffer[idx*2+1] = word
+	return decodeKana(buffer, 0x25, []rune("ヽヾー。「」、・"))
+}
+
+// Decode kana set whose codes 0x21-0x76 are placed at row of JIS, and 0x77-0x7E are symbols.
+// REF ARIB STD-B24 第一編 第2部 表7-7, 表7-8
+func decodeKana(buffer []byte, row byte, symbols []rune) string {
+	str := ""
+	jisBuffer := []byte{}
+	for _, word := range buffer {
+		if 0x77 <= word && 0x7E >= word {
+			if 0 < len(jisBuffer) {
+				str += decodeKanji(append(ESC_KANJI, jisBuffer...))
+				jisBuffer = []byte{}
+			}
+			str += string(symbols[word-0x77])
+			continue
+		}
+		jisBuffer = append(jisBuffer, row, word)
+	}
+	if 0 < len(jisBuffer) {
+		str += decodeKanji(append(ESC_KANJI, jisBuffer...))
 	}
+	return str
+}
 
-	return decodeKanji(append(ESC_KANJI, jisBuffer...))
+// Returns byte size of one character in graphic set.
+func characterSize(finalByte byte) int {
+	switch finalByte {
+	case KANJI, JIS_COMPATI_KANJI_1, JIS_COMPATI_KANJI_2, ADDITIONAL_SYMBOL:
+		return 2
+	}
+	return 1
 }
 
 func getWordType(word byte) byte {
@@ -0,0 +1,109 @@
+package mpeg2ts
+
+import (
+	"time"
+)
+
+type (
+	// Clock maps PCR, PTS and DTS to wall-clock time by correlating TDT/TOT with PCR.
+	Clock struct {
+		pcrPid    uint
+		hasPcrPid bool
+
+		lastPCR uint64 // 27MHz unit
+		hasPCR  bool
+
+		referencePCR  uint64
+		referenceTime time.Time
+		hasReference  bool
+	}
+)
+
+const (
+	// PCR wraps around at 2^33 * 300.
+	PCR_CYCLE = (uint64(1) << 33) * 300
+
+	// Allowed difference between predicted time and TDT/TOT time.
+	// NOTE TDT/TOT has only second accuracy.
+	CLOCK_TOLERANCE = time.Second
+)
+
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// SetPcrPid sets PID whose PCR is used. If it is not set, first PID which has PCR is used.
+func (c *Clock) SetPcrPid(pid uint) {
+	if c.hasPcrPid && c.pcrPid == pid {
+		return
+	}
+	c.pcrPid = pid
+	c.hasPcrPid = true
+	c.hasPCR = false
+	c.hasReference = false
+}
+
+// UpdatePCR is called with each packet which has PCR.
+func (c *Clock) UpdatePCR(pid uint, adaptation *AdaptationField) {
+	if nil == adaptation || !adaptation.PCRFlag {
+		return
+	}
+	if !c.hasPcrPid {
+		c.SetPcrPid(pid)
+	}
+	if c.pcrPid != pid {
+		return
+	}
+	if adaptation.DiscontinuityIndicator {
+		// Time base is changed, so reference is not valid anymore.
+		c.hasReference = false
+	}
+	c.lastPCR = adaptation.PCR.Value()
+	c.hasPCR = true
+}
+
+// UpdateTime is called with time of TDT/TOT.
+// Reference is updated only when t is not close to predicted time.
+func (c *Clock) UpdateTime(t time.Time) {
+	if !c.hasPCR {
+		return
+	}
+	if c.hasReference {
+		predicted, _ := c.Time(c.lastPCR)
+		difference := t.Sub(predicted)
+		if -CLOCK_TOLERANCE < difference && CLOCK_TOLERANCE > difference {
+			return
+		}
+	}
+	c.referencePCR = c.lastPCR
+	c.referenceTime = t
+	c.hasReference = true
+}
+
+// Time returns wall-clock time of pcr. (27MHz unit)
+// It returns false when TDT/TOT is not received yet.
+func (c *Clock) Time(pcr uint64) (time.Time, bool) {
+	if !c.hasReference {
+		return time.Time{}, false
+	}
+	// Nearest difference with considering wrap around.
+	difference := int64((pcr + PCR_CYCLE - c.referencePCR%PCR_CYCLE) % PCR_CYCLE)
+	if int64(PCR_CYCLE/2) < difference {
+		difference -= int64(PCR_CYCLE)
+	}
+	// 27MHz to nanosecond (1000 / 27)
+	return c.referenceTime.Add(time.Duration(difference * 1000 / 27)), true
+}
+
+// TimestampTime returns wall-clock time of PTS or DTS. (90kHz unit)
+func (c *Clock) TimestampTime(timestamp uint64) (time.Time, bool) {
+	return c.Time(timestamp * 300)
+}
+
+// Now returns wall-clock time of last PCR.
+func (c *Clock) Now() (time.Time, bool) {
+	if !c.hasPCR {
+		return time.Time{}, false
+	}
+	return c.Time(c.lastPCR)
+}
@@ -0,0 +1,80 @@
+// epgdump reads ts file and writes EPG of EIT and SDT as XMLTV document.
+//
+//	epgdump [-o output.xml] [-tz Asia/Tokyo] [-size 188] input.ts
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"mpeg2ts"
+	"mpeg2ts/psi"
+)
+
+func main() {
+	output := flag.String("o", "", "output file of XMLTV (default is stdout)")
+	timeZone := flag.String("tz", "", "time zone of EIT (default is JST)")
+	packetSize := flag.Int("size", 0, "packet size 188, 192 or 204 (default is auto detection)")
+	quiet := flag.Bool("q", false, "do not report errors of broken packets and sections")
+	flag.Parse()
+
+	if 1 != flag.NArg() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] input.ts\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	err := run(flag.Arg(0), *output, *timeZone, *packetSize, *quiet)
+	if nil != err {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(input string, output string, timeZone string, packetSize int, quiet bool) error {
+	epg := mpeg2ts.NewEPG()
+
+	parser := mpeg2ts.NewParser()
+	parser.PacketSize = packetSize
+	if "" != timeZone {
+		loc, err := time.LoadLocation(timeZone)
+		if nil != err {
+			return err
+		}
+		parser.SetLocation(loc)
+	}
+	parser.Handler = mpeg2ts.Handler{
+		OnEIT: func(pid uint, eit *psi.EITField) {
+			epg.Push(eit)
+		},
+		OnSDT: func(pid uint, sdt *psi.SDTField) {
+			epg.PushSdt(sdt)
+		},
+		// Broken packets and sections are skipped.
+		OnError: func(err error) {
+			if !quiet {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		},
+	}
+	err := parser.Parse(input)
+	if nil != err {
+		return err
+	}
+
+	if "" == output {
+		return mpeg2ts.WriteXMLTV(os.Stdout, epg)
+	}
+	fp, err := os.Create(output)
+	if nil != err {
+		return err
+	}
+	err = mpeg2ts.WriteXMLTV(fp, epg)
+	if nil != err {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
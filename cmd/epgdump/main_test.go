@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mpeg2ts"
+	"mpeg2ts/psi"
+)
+
+// Returns packet of pid which has section whose section_length and CRC_32 are filled.
+func newTestPacket(pid uint16, tableId byte, body []byte) []byte {
+	length := len(body) + psi.CRC_LENGTH
+	section := append([]byte{tableId, 0xF0 | byte(length>>8), byte(length)}, body...)
+	section = append(section, make([]byte, psi.CRC_LENGTH)...)
+	binary.BigEndian.PutUint32(section[len(section)-psi.CRC_LENGTH:], psi.Crc32(section[:len(section)-psi.CRC_LENGTH]))
+
+	packet := []byte{mpeg2ts.SYNC_BYTE, 0x40 | byte(pid>>8), byte(pid), 0x10, 0x00}
+	packet = append(packet, section...)
+	return append(packet, bytes.Repeat([]byte{mpeg2ts.STUFFING_BYTE}, mpeg2ts.PACKET_SIZE-len(packet))...)
+}
+
+// Returns ts which has SDT and EIT present of service 0x0065.
+// Name of service is 山田 and name of event is 出演. (JIS X 0208)
+func newTestStream() []byte {
+	serviceDescriptor := []byte{psi.ServiceTag, 0x07, 0x01, 0x00, 0x04, 0x3B, 0x33, 0x45, 0x44}
+	sdt := []byte{0x40, 0x10, 0xC1, 0x00, 0x00, 0x00, 0x04, 0xFF, 0x00, 0x65, 0xFF, 0x80, byte(len(serviceDescriptor))}
+	sdt = append(sdt, serviceDescriptor...)
+
+	eventDescriptor := []byte{psi.EventTag, 0x09, 'j', 'p', 'n', 0x04, 0x3D, 0x50, 0x31, 0x69, 0x00}
+	eit := []byte{
+		0x00, 0x65, 0xC1, 0x00, 0x01, 0x40, 0x10, 0x00, 0x04, 0x01, psi.EIT_PF_ACTUAL_TABLE_ID,
+		0x00, 0x01, 0xC0, 0x79, 0x12, 0x45, 0x00, 0x01, 0x00, 0x00, 0x80, byte(len(eventDescriptor)),
+	}
+	eit = append(eit, eventDescriptor...)
+
+	stream := newTestPacket(0x0011, psi.SDT_ACTUAL_TABLE_ID, sdt)
+	return append(stream, newTestPacket(0x0012, psi.EIT_PF_ACTUAL_TABLE_ID, eit)...)
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epgdump")
+	if nil != err {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	input := filepath.Join(dir, "input.ts")
+	err = ioutil.WriteFile(input, newTestStream(), 0600)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		timeZone string
+		contains []string
+	}{
+		{
+			name:     "JST",
+			timeZone: "",
+			contains: []string{
+				`<channel id="4.16400.101">`,
+				`<display-name>山田</display-name>`,
+				`<programme start="19931013124500 +0900" stop="19931013134500 +0900" channel="4.16400.101">`,
+				`<title lang="ja">出演</title>`,
+			},
+		},
+		{
+			name:     "UTC",
+			timeZone: "UTC",
+			contains: []string{
+				`<programme start="19931013124500 +0000" stop="19931013134500 +0000" channel="4.16400.101">`,
+			},
+		},
+	}
+	for _, c := range cases {
+		output := filepath.Join(dir, c.name+".xml")
+		err := run(input, output, c.timeZone, 0, false)
+		if nil != err {
+			t.Fatalf("%s: run returns %v", c.name, err)
+		}
+		written, err := ioutil.ReadFile(output)
+		if nil != err {
+			t.Fatal(err)
+		}
+		for _, s := range c.contains {
+			if !strings.Contains(string(written), s) {
+				t.Errorf("%s: output does not contain %s\n%s", c.name, s, string(written))
+			}
+		}
+	}
+}
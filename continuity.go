@@ -0,0 +1,125 @@
+package mpeg2ts
+
+type (
+	// ContinuityChecker tracks continuity_counter of each PID.
+	ContinuityChecker struct {
+		states     map[uint]*continuityState
+		statistics map[uint]*PidStatistics
+
+		// OnDrop is called when discontinuity of continuity_counter is detected.
+		OnDrop func(event DropEvent)
+	}
+
+	continuityState struct {
+		counter    byte
+		duplicated bool
+	}
+
+	DropEvent struct {
+		Pid      uint
+		Offset   int64 // stream offset of packet
+		Expected byte
+		Got      byte
+		Lost     byte // (Got - Expected) mod 16
+	}
+
+	PidStatistics struct {
+		Packets     uint64
+		Drops       uint64 // count of discontinuity events
+		LostPackets uint64 // count of lost packets which is estimated by continuity_counter
+		Errors      uint64 // packets which have transport_error_indicator, or repeat continuity_counter more than twice
+		Scrambled   uint64
+	}
+)
+
+const NULL_PID = 0x1FFF
+
+// Results of ContinuityChecker.Check
+const (
+	CONTINUITY_OK = iota
+	CONTINUITY_DUPLICATED
+	CONTINUITY_DROPPED
+)
+
+func NewContinuityChecker() (c *ContinuityChecker) {
+	c = &ContinuityChecker{
+		states:     map[uint]*continuityState{},
+		statistics: map[uint]*PidStatistics{},
+	}
+	return c
+}
+
+// Check checks continuity_counter of packet which is placed at offset.
+// It returns CONTINUITY_OK, CONTINUITY_DUPLICATED or CONTINUITY_DROPPED.
+func (c *ContinuityChecker) Check(packet *Packet, offset int64) int {
+	if NULL_PID == packet.Pid {
+		return CONTINUITY_OK
+	}
+
+	statistics, ok := c.statistics[packet.Pid]
+	if !ok {
+		statistics = &PidStatistics{}
+		c.statistics[packet.Pid] = statistics
+	}
+	statistics.Packets++
+	if 0 != packet.TransportScramblingControl {
+		statistics.Scrambled++
+	}
+	if packet.TransportErrorIndicator {
+		// NOTE header of error packet is not reliable.
+		statistics.Errors++
+		return CONTINUITY_OK
+	}
+
+	state, ok := c.states[packet.Pid]
+	if !ok || (nil != packet.Adaptation && packet.Adaptation.DiscontinuityIndicator) {
+		c.states[packet.Pid] = &continuityState{
+			counter: packet.ContinuityCounter,
+		}
+		return CONTINUITY_OK
+	}
+
+	// continuity_counter is not incremented when packet has no payload.
+	expected := state.counter
+	if packet.HavePayload() {
+		if state.counter == packet.ContinuityCounter {
+			if state.duplicated {
+				// NOTE only one duplicate packet is allowed, so more repetition is error. (not lost packets)
+				statistics.Errors++
+			}
+			state.duplicated = true
+			return CONTINUITY_DUPLICATED
+		}
+		expected = (state.counter + 1) & 0x0F
+	}
+
+	state.counter = packet.ContinuityCounter
+	state.duplicated = false
+	if expected == packet.ContinuityCounter {
+		return CONTINUITY_OK
+	}
+
+	// NOTE lost packets are counted modulo 16, because continuity_counter has 4 bits.
+	lost := (packet.ContinuityCounter - expected) & 0x0F
+	statistics.Drops++
+	statistics.LostPackets += uint64(lost)
+	if nil != c.OnDrop {
+		c.OnDrop(DropEvent{
+			Pid:      packet.Pid,
+			Offset:   offset,
+			Expected: expected,
+			Got:      packet.ContinuityCounter,
+			Lost:     lost,
+		})
+	}
+	return CONTINUITY_DROPPED
+}
+
+// Statistics returns copy of statistics of each PID.
+func (c *ContinuityChecker) Statistics() map[uint]PidStatistics {
+	statistics := make(map[uint]PidStatistics, len(c.statistics))
+	for pid, s := range c.statistics {
+		statistics[pid] = *s
+	}
+	return statistics
+}
@@ -0,0 +1,84 @@
+package mpeg2ts
+
+import (
+	"testing"
+)
+
+// Returns packet of PID 0x0100 which has payload. (adaptation_field_control is 01)
+func newTestPacket(counter byte) *Packet {
+	return &Packet{
+		Pid:                    0x0100,
+		AdaptationFieldControl: 0x01,
+		ContinuityCounter:      counter,
+	}
+}
+
+// Returns packet of PID 0x0100 which has only adaptation field. (adaptation_field_control is 10)
+func newTestAdaptationPacket(counter byte) *Packet {
+	return &Packet{
+		Pid:                    0x0100,
+		AdaptationFieldControl: 0x02,
+		ContinuityCounter:      counter,
+		Adaptation:             &AdaptationField{},
+	}
+}
+
+func TestContinuityCheckerCheck(t *testing.T) {
+	cases := []struct {
+		name    string
+		packets []*Packet
+		results []int
+		drops   uint64
+		lost    uint64
+		errors  uint64
+	}{
+		{
+			name:    "wrap",
+			packets: []*Packet{newTestPacket(14), newTestPacket(15), newTestPacket(0), newTestPacket(1)},
+			results: []int{CONTINUITY_OK, CONTINUITY_OK, CONTINUITY_OK, CONTINUITY_OK},
+		},
+		{
+			name:    "duplicate",
+			packets: []*Packet{newTestPacket(3), newTestPacket(3), newTestPacket(4)},
+			results: []int{CONTINUITY_OK, CONTINUITY_DUPLICATED, CONTINUITY_OK},
+		},
+		{
+			name:    "second duplicate",
+			packets: []*Packet{newTestPacket(3), newTestPacket(3), newTestPacket(3), newTestPacket(4)},
+			results: []int{CONTINUITY_OK, CONTINUITY_DUPLICATED, CONTINUITY_DUPLICATED, CONTINUITY_OK},
+			errors:  1,
+		},
+		{
+			name:    "adaptation only",
+			packets: []*Packet{newTestPacket(5), newTestAdaptationPacket(5), newTestAdaptationPacket(5), newTestPacket(6)},
+			results: []int{CONTINUITY_OK, CONTINUITY_OK, CONTINUITY_OK, CONTINUITY_OK},
+		},
+		{
+			name:    "drop",
+			packets: []*Packet{newTestPacket(5), newTestPacket(9), newTestPacket(10)},
+			results: []int{CONTINUITY_OK, CONTINUITY_DROPPED, CONTINUITY_OK},
+			drops:   1,
+			lost:    3,
+		},
+		{
+			name:    "drop over wrap",
+			packets: []*Packet{newTestPacket(14), newTestPacket(2), newTestPacket(4)},
+			results: []int{CONTINUITY_OK, CONTINUITY_DROPPED, CONTINUITY_DROPPED},
+			drops:   2,
+			lost:    4,
+		},
+	}
+	for _, c := range cases {
+		checker := NewContinuityChecker()
+		for idx, packet := range c.packets {
+			result := checker.Check(packet, int64(idx*PACKET_SIZE))
+			if c.results[idx] != result {
+				t.Errorf("%s: packet %d is %d, want %d", c.name, idx, result, c.results[idx])
+			}
+		}
+		statistics := checker.Statistics()[0x0100]
+		if uint64(len(c.packets)) != statistics.Packets || c.drops != statistics.Drops || c.lost != statistics.LostPackets || c.errors != statistics.Errors {
+			t.Errorf("%s: statistics is %+v, want %d drops, %d lost packets and %d errors", c.name, statistics, c.drops, c.lost, c.errors)
+		}
+	}
+}
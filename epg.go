@@ -0,0 +1,301 @@
+package mpeg2ts
+
+import (
+	"sort"
+	"time"
+
+	"mpeg2ts/psi"
+)
+
+type (
+	// EPG aggregates events of EIT present/following and schedule by service, and names of services in SDT.
+	// Sections of not applicable EIT (current_next_indicator is 0) are ignored.
+	// NOTE events of section are replaced when the section is updated, and all events of table
+	// are removed when version_number of table is changed.
+	EPG struct {
+		services map[ServiceKey]*epgService
+	}
+
+	ServiceKey struct {
+		OriginalNetworkId uint
+		TransportStreamId uint
+		ServiceId         uint
+	}
+
+	EventKey struct {
+		ServiceKey
+		EventId uint16
+	}
+
+	epgService struct {
+		// Name of service in SDT.
+		name string
+
+		// EIT sub tables of service by table_id.
+		tables map[byte]*epgTable
+	}
+
+	epgTable struct {
+		version           byte
+		lastSectionNumber byte
+		lastTableId       byte
+		sections          map[byte][]psi.EITEvent
+		// segment_last_section_number by segment.
+		segmentLastSectionNumbers map[byte]byte
+	}
+)
+
+const (
+	// Schedule table is divided into segments of 8 sections. (3 hours)
+	EIT_SEGMENT_SECTIONS = 8
+)
+
+func NewEPG() *EPG {
+	return &EPG{
+		services: map[ServiceKey]*epgService{},
+	}
+}
+
+// Push merges events of EIT section.
+func (epg *EPG) Push(eit *psi.EITField) {
+	if nil == eit || !eit.NextIndicator || !psi.IsEitTableId(eit.TableId) {
+		return
+	}
+	key := ServiceKey{
+		OriginalNetworkId: eit.OriginalNetworkId,
+		TransportStreamId: eit.TransportStreamId,
+		ServiceId:         eit.ServiceId,
+	}
+	service := epg.service(key)
+	table, ok := service.tables[eit.TableId]
+	if !ok || table.version != eit.Version || table.lastSectionNumber != eit.LastSectionNumber {
+		table = &epgTable{
+			version:                   eit.Version,
+			lastSectionNumber:         eit.LastSectionNumber,
+			sections:                  map[byte][]psi.EITEvent{},
+			segmentLastSectionNumbers: map[byte]byte{},
+		}
+		service.tables[eit.TableId] = table
+	}
+	table.lastTableId = eit.LastTableId
+	table.sections[eit.SectionNumber] = eit.Events
+	table.segmentLastSectionNumbers[eit.SectionNumber/EIT_SEGMENT_SECTIONS] = eit.SegmentLastSectionNumber
+}
+
+// PushSdt sets names of services in SDT section.
+func (epg *EPG) PushSdt(sdt *psi.SDTField) {
+	if nil == sdt || !sdt.CurrentNextIndicator {
+		return
+	}
+	for _, s := range sdt.Services {
+		sd, ok := s.ServiceDescriptor()
+		if !ok {
+			continue
+		}
+		key := ServiceKey{
+			OriginalNetworkId: sdt.OriginalNetworkId,
+			TransportStreamId: sdt.TransportStreamId,
+			ServiceId:         s.ServiceId,
+		}
+		service := epg.service(key)
+		service.name = sd.ServiceName
+	}
+}
+
+// ServiceName returns name of service in SDT.
+func (epg *EPG) ServiceName(key ServiceKey) (string, bool) {
+	service, ok := epg.services[key]
+	if !ok || "" == service.name {
+		return "", false
+	}
+	return service.name, true
+}
+
+// Services returns keys of services which have SDT or EIT.
+func (epg *EPG) Services() []ServiceKey {
+	keys := make([]ServiceKey, 0, len(epg.services))
+	for key := range epg.services {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].OriginalNetworkId != keys[j].OriginalNetworkId {
+			return keys[i].OriginalNetworkId < keys[j].OriginalNetworkId
+		}
+		if keys[i].TransportStreamId != keys[j].TransportStreamId {
+			return keys[i].TransportStreamId < keys[j].TransportStreamId
+		}
+		return keys[i].ServiceId < keys[j].ServiceId
+	})
+	return keys
+}
+
+// Event returns event of key.
+// If event is in both present/following and schedule, event of present/following is returned.
+func (epg *EPG) Event(key EventKey) (psi.EITEvent, bool) {
+	events := epg.events(key.ServiceKey)
+	event, ok := events[key.EventId]
+	return event, ok
+}
+
+// Events returns events of service which overlap [from, to) in order of start_time.
+// Zero from or to means unlimited. Events whose start_time is undefined are not returned,
+// and events whose duration is undefined are regarded as continuing.
+func (epg *EPG) Events(key ServiceKey, from, to time.Time) []psi.EITEvent {
+	events := []psi.EITEvent{}
+	for _, event := range epg.events(key) {
+		if event.StartTime.IsZero() {
+			continue
+		}
+		if !to.IsZero() && !event.StartTime.Before(to) {
+			continue
+		}
+		if endTime, ok := event.EndTime(); ok && !from.IsZero() && !endTime.After(from) {
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].StartTime.Equal(events[j].StartTime) {
+			return events[i].StartTime.Before(events[j].StartTime)
+		}
+		return events[i].EventId < events[j].EventId
+	})
+	return events
+}
+
+// IsSegmentComplete returns whether all sections of segment in EIT sub table are received.
+func (epg *EPG) IsSegmentComplete(key ServiceKey, tableId byte, segment byte) bool {
+	service, ok := epg.services[key]
+	if !ok {
+		return false
+	}
+	table, ok := service.tables[tableId]
+	if !ok {
+		return false
+	}
+	return table.isSegmentComplete(segment)
+}
+
+// IsComplete returns whether all segments of EIT sub table are received.
+func (epg *EPG) IsComplete(key ServiceKey, tableId byte) bool {
+	service, ok := epg.services[key]
+	if !ok {
+		return false
+	}
+	table, ok := service.tables[tableId]
+	if !ok {
+		return false
+	}
+	for segment := 0; segment <= int(table.lastSectionNumber)/EIT_SEGMENT_SECTIONS; segment++ {
+		if !table.isSegmentComplete(byte(segment)) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsScheduleComplete returns whether all schedule tables (actual or other) of service are received
+// up to last_table_id.
+func (epg *EPG) IsScheduleComplete(key ServiceKey, actual bool) bool {
+	firstTableId := byte(psi.EIT_SCHEDULE_ACTUAL_TABLE_ID)
+	if !actual {
+		firstTableId = psi.EIT_SCHEDULE_OTHER_TABLE_ID
+	}
+	service, ok := epg.services[key]
+	if !ok {
+		return false
+	}
+	first, ok := service.tables[firstTableId]
+	if !ok {
+		return false
+	}
+	for tableId := int(firstTableId); tableId <= int(first.lastTableId); tableId++ {
+		if !epg.IsComplete(key, byte(tableId)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Expire removes events which end before t from all services.
+// Events whose start_time or duration is undefined are not removed.
+func (epg *EPG) Expire(t time.Time) {
+	for _, service := range epg.services {
+		for _, table := range service.tables {
+			for number, events := range table.sections {
+				kept := []psi.EITEvent{}
+				for _, event := range events {
+					if endTime, ok := event.EndTime(); !ok || endTime.After(t) {
+						kept = append(kept, event)
+					}
+				}
+				table.sections[number] = kept
+			}
+		}
+	}
+}
+
+// Remove removes all events of service.
+func (epg *EPG) Remove(key ServiceKey) {
+	delete(epg.services, key)
+}
+
+// Returns service of key, and adds it if it does not exist.
+func (epg *EPG) service(key ServiceKey) *epgService {
+	service, ok := epg.services[key]
+	if !ok {
+		service = &epgService{
+			tables: map[byte]*epgTable{},
+		}
+		epg.services[key] = service
+	}
+	return service
+}
+
+// Merge events of all sub tables of service.
+// Present/following tables precede schedule tables, and smaller table_id precedes.
+func (epg *EPG) events(key ServiceKey) map[uint16]psi.EITEvent {
+	events := map[uint16]psi.EITEvent{}
+	service, ok := epg.services[key]
+	if !ok {
+		return events
+	}
+	tableIds := make([]int, 0, len(service.tables))
+	for tableId := range service.tables {
+		tableIds = append(tableIds, int(tableId))
+	}
+	sort.Ints(tableIds)
+	for _, tableId := range tableIds {
+		table := service.tables[byte(tableId)]
+		numbers := make([]int, 0, len(table.sections))
+		for number := range table.sections {
+			numbers = append(numbers, int(number))
+		}
+		sort.Ints(numbers)
+		for _, number := range numbers {
+			for _, event := range table.sections[byte(number)] {
+				if _, ok := events[event.EventId]; !ok {
+					events[event.EventId] = event
+				}
+			}
+		}
+	}
+	return events
+}
+
+func (table *epgTable) isSegmentComplete(segment byte) bool {
+	first := int(segment) * EIT_SEGMENT_SECTIONS
+	if int(table.lastSectionNumber) < first {
+		return false
+	}
+	last, ok := table.segmentLastSectionNumbers[segment]
+	if !ok {
+		return false
+	}
+	for number := first; number <= int(last); number++ {
+		if _, ok := table.sections[byte(number)]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,264 @@
+package mpeg2ts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mpeg2ts/psi"
+)
+
+var testServiceKey = ServiceKey{OriginalNetworkId: 4, TransportStreamId: 0x4010, ServiceId: 0x0065}
+
+type eitSection struct {
+	tableId           byte
+	version           byte
+	sectionNumber     byte
+	lastSectionNumber byte
+	segmentLastNumber byte
+	lastTableId       byte
+}
+
+// Returns EIT section of testServiceKey.
+func newTestEit(s eitSection, events ...psi.EITEvent) *psi.EITField {
+	return &psi.EITField{
+		Common:                   psi.Common{TableId: s.tableId},
+		ServiceId:                testServiceKey.ServiceId,
+		Version:                  s.version,
+		NextIndicator:            true,
+		SectionNumber:            s.sectionNumber,
+		LastSectionNumber:        s.lastSectionNumber,
+		TransportStreamId:        testServiceKey.TransportStreamId,
+		OriginalNetworkId:        testServiceKey.OriginalNetworkId,
+		SegmentLastSectionNumber: s.segmentLastNumber,
+		LastTableId:              s.lastTableId,
+		Events:                   events,
+	}
+}
+
+// Returns event which starts at hour:minute of 2020-01-01 in JST. (0 duration means undefined)
+func newTestEvent(eventId uint16, hour int, minute int, duration time.Duration) psi.EITEvent {
+	return psi.EITEvent{
+		EventId:           eventId,
+		StartTime:         time.Date(2020, 1, 1, hour, minute, 0, 0, psi.JST),
+		Duration:          duration,
+		DurationUndefined: 0 == duration,
+	}
+}
+
+func eventIds(events []psi.EITEvent) []uint16 {
+	ids := []uint16{}
+	for _, event := range events {
+		ids = append(ids, event.EventId)
+	}
+	return ids
+}
+
+func TestEPGIsSegmentComplete(t *testing.T) {
+	const schedule = psi.EIT_SCHEDULE_ACTUAL_TABLE_ID
+
+	cases := []struct {
+		name     string
+		sections []eitSection
+		segments []bool // completeness of segment 0 and 1
+		complete bool
+		schedule bool
+	}{
+		{
+			name:     "segment_last_section_number",
+			sections: []eitSection{{schedule, 0, 0, 8, 1, schedule}, {schedule, 0, 1, 8, 1, schedule}},
+			segments: []bool{true, false},
+		},
+		{
+			name:     "missing section",
+			sections: []eitSection{{schedule, 0, 0, 8, 2, schedule}, {schedule, 0, 2, 8, 2, schedule}},
+			segments: []bool{false, false},
+		},
+		{
+			name: "all segments",
+			sections: []eitSection{
+				{schedule, 0, 0, 8, 1, schedule}, {schedule, 0, 1, 8, 1, schedule},
+				{schedule, 0, 8, 8, 8, schedule},
+			},
+			segments: []bool{true, true},
+			complete: true,
+			schedule: true,
+		},
+		{
+			name: "next table is not received",
+			sections: []eitSection{
+				{schedule, 0, 0, 8, 0, schedule + 1}, {schedule, 0, 8, 8, 8, schedule + 1},
+			},
+			segments: []bool{true, true},
+			complete: true,
+		},
+		{
+			name: "all tables",
+			sections: []eitSection{
+				{schedule, 0, 0, 8, 0, schedule + 1}, {schedule, 0, 8, 8, 8, schedule + 1},
+				{schedule + 1, 0, 0, 0, 0, schedule + 1},
+			},
+			segments: []bool{true, true},
+			complete: true,
+			schedule: true,
+		},
+		{
+			name: "version change",
+			sections: []eitSection{
+				{schedule, 0, 0, 8, 1, schedule}, {schedule, 0, 1, 8, 1, schedule},
+				{schedule, 1, 0, 8, 1, schedule}, {schedule, 1, 8, 8, 8, schedule},
+			},
+			segments: []bool{false, true},
+		},
+		{
+			name: "last_section_number change",
+			sections: []eitSection{
+				{schedule, 0, 0, 8, 0, schedule}, {schedule, 0, 8, 8, 8, schedule},
+				{schedule, 0, 0, 0, 0, schedule},
+			},
+			segments: []bool{true, false},
+			complete: true,
+			schedule: true,
+		},
+	}
+	for _, c := range cases {
+		epg := NewEPG()
+		for _, s := range c.sections {
+			epg.Push(newTestEit(s))
+		}
+		for segment, complete := range c.segments {
+			if result := epg.IsSegmentComplete(testServiceKey, schedule, byte(segment)); complete != result {
+				t.Errorf("%s: IsSegmentComplete of segment %d returns %v, want %v", c.name, segment, result, complete)
+			}
+		}
+		if result := epg.IsComplete(testServiceKey, schedule); c.complete != result {
+			t.Errorf("%s: IsComplete returns %v, want %v", c.name, result, c.complete)
+		}
+		if result := epg.IsScheduleComplete(testServiceKey, true); c.schedule != result {
+			t.Errorf("%s: IsScheduleComplete returns %v, want %v", c.name, result, c.schedule)
+		}
+	}
+}
+
+func TestEPGPush(t *testing.T) {
+	const schedule = psi.EIT_SCHEDULE_ACTUAL_TABLE_ID
+	first := newTestEvent(1, 10, 0, time.Hour)
+	second := newTestEvent(2, 11, 0, time.Hour)
+	third := newTestEvent(3, 12, 0, time.Hour)
+
+	cases := []struct {
+		name     string
+		sections []*psi.EITField
+		events   []uint16
+	}{
+		{
+			name: "sections",
+			sections: []*psi.EITField{
+				newTestEit(eitSection{schedule, 0, 0, 1, 1, schedule}, first),
+				newTestEit(eitSection{schedule, 0, 1, 1, 1, schedule}, second, third),
+			},
+			events: []uint16{1, 2, 3},
+		},
+		{
+			name: "section update",
+			sections: []*psi.EITField{
+				newTestEit(eitSection{schedule, 0, 0, 1, 1, schedule}, first, second),
+				newTestEit(eitSection{schedule, 0, 0, 1, 1, schedule}, first),
+			},
+			events: []uint16{1},
+		},
+		{
+			name: "version change",
+			sections: []*psi.EITField{
+				newTestEit(eitSection{schedule, 0, 0, 1, 1, schedule}, first),
+				newTestEit(eitSection{schedule, 0, 1, 1, 1, schedule}, second),
+				newTestEit(eitSection{schedule, 1, 1, 1, 1, schedule}, third),
+			},
+			events: []uint16{3},
+		},
+		{
+			name: "last_section_number change",
+			sections: []*psi.EITField{
+				newTestEit(eitSection{schedule, 0, 0, 1, 1, schedule}, first),
+				newTestEit(eitSection{schedule, 0, 1, 2, 2, schedule}, second),
+			},
+			events: []uint16{2},
+		},
+		{
+			name: "not applicable section",
+			sections: []*psi.EITField{
+				newTestEit(eitSection{schedule, 0, 0, 1, 1, schedule}, first),
+				func() *psi.EITField {
+					eit := newTestEit(eitSection{schedule, 1, 0, 1, 1, schedule}, second)
+					eit.NextIndicator = false
+					return eit
+				}(),
+			},
+			events: []uint16{1},
+		},
+	}
+	for _, c := range cases {
+		epg := NewEPG()
+		for _, eit := range c.sections {
+			epg.Push(eit)
+		}
+		if ids := eventIds(epg.Events(testServiceKey, time.Time{}, time.Time{})); !reflect.DeepEqual(c.events, ids) {
+			t.Errorf("%s: events are %v, want %v", c.name, ids, c.events)
+		}
+	}
+}
+
+func TestEPGEvents(t *testing.T) {
+	epg := NewEPG()
+	epg.Push(newTestEit(eitSection{psi.EIT_SCHEDULE_ACTUAL_TABLE_ID, 0, 0, 0, 0, psi.EIT_SCHEDULE_ACTUAL_TABLE_ID},
+		newTestEvent(3, 12, 0, 0),
+		newTestEvent(1, 10, 0, time.Hour),
+		newTestEvent(2, 11, 0, time.Hour),
+	))
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2020, 1, 1, hour, minute, 0, 0, psi.JST)
+	}
+
+	cases := []struct {
+		name   string
+		from   time.Time
+		to     time.Time
+		events []uint16
+	}{
+		{"unlimited", time.Time{}, time.Time{}, []uint16{1, 2, 3}},
+		{"end of event is excluded", at(11, 0), at(12, 0), []uint16{2}},
+		{"start of event is excluded", at(10, 30), at(11, 0), []uint16{1}},
+		{"overlap", at(10, 59), at(11, 1), []uint16{1, 2}},
+		{"undefined duration continues", at(23, 0), time.Time{}, []uint16{3}},
+		{"before all events", time.Time{}, at(10, 0), []uint16{}},
+	}
+	for _, c := range cases {
+		if ids := eventIds(epg.Events(testServiceKey, c.from, c.to)); !reflect.DeepEqual(c.events, ids) {
+			t.Errorf("%s: events are %v, want %v", c.name, ids, c.events)
+		}
+	}
+}
+
+func TestEPGExpire(t *testing.T) {
+	cases := []struct {
+		name   string
+		at     time.Time
+		events []uint16
+	}{
+		{"before all events", time.Date(2020, 1, 1, 9, 0, 0, 0, psi.JST), []uint16{1, 2, 3}},
+		{"end of event", time.Date(2020, 1, 1, 11, 0, 0, 0, psi.JST), []uint16{2, 3}},
+		{"after all events", time.Date(2020, 1, 2, 0, 0, 0, 0, psi.JST), []uint16{3}},
+	}
+	for _, c := range cases {
+		epg := NewEPG()
+		epg.Push(newTestEit(eitSection{psi.EIT_PF_ACTUAL_TABLE_ID, 0, 0, 1, 1, psi.EIT_PF_ACTUAL_TABLE_ID}, newTestEvent(1, 10, 0, time.Hour)))
+		epg.Push(newTestEit(eitSection{psi.EIT_SCHEDULE_ACTUAL_TABLE_ID, 0, 0, 0, 0, psi.EIT_SCHEDULE_ACTUAL_TABLE_ID},
+			newTestEvent(2, 11, 0, time.Hour),
+			newTestEvent(3, 12, 0, 0),
+		))
+		epg.Expire(c.at)
+		if ids := eventIds(epg.Events(testServiceKey, time.Time{}, time.Time{})); !reflect.DeepEqual(c.events, ids) {
+			t.Errorf("%s: events are %v, want %v", c.name, ids, c.events)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package mpeg2ts
+
+import (
+	"fmt"
+
+	"mpeg2ts/psi"
+)
+
+type (
+	// Handler has callbacks which are called by Parser.
+	// Each callback is optional. (nil is ignored)
+	Handler struct {
+		// OnResync is called when some bytes are skipped to find sync byte.
+		OnResync func(event ResyncEvent)
+
+		// OnDrop is called when discontinuity of continuity_counter is detected.
+		OnDrop func(event DropEvent)
+
+		// Called when each table is parsed.
+		OnPAT func(pid uint, pat *psi.PATField)
+		OnCAT func(pid uint, cat *psi.CATField)
+		OnPMT func(pid uint, pmt *psi.PMTField)
+		OnNIT func(pid uint, nit *psi.NITField)
+		OnSDT func(pid uint, sdt *psi.SDTField)
+		OnBAT func(pid uint, bat *psi.BATField)
+		OnEIT func(pid uint, eit *psi.EITField)
+		OnTDT func(pid uint, tdt *psi.TDTField)
+		OnTOT func(pid uint, tot *psi.TOTField)
+
+		// OnTable is called with every parsed table. (after typed callback)
+		OnTable func(pid uint, table interface{})
+
+		// OnTableChange is called when version of table is changed.
+		OnTableChange func(change TableChange)
+
+		// OnError is called when packet or table can not be parsed, and parsing is continued.
+		// If OnError is nil, parsing is stopped and the error is returned.
+		OnError func(err error)
+	}
+
+	// Error which is occurred in parsing packet or table of Pid.
+	ParseError struct {
+		Pid    uint  // NULL_PID when packet itself is broken
+		Offset int64 // stream offset of packet
+		Err    error
+	}
+)
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("PID 0x%04X (offset %d): %s", e.Pid, e.Offset, e.Err.Error())
+}
+
+// Call typed callback and OnTable.
+func (h *Handler) dispatch(pid uint, table interface{}) {
+	switch t := table.(type) {
+	case *psi.PATField:
+		if nil != h.OnPAT {
+			h.OnPAT(pid, t)
+		}
+	case *psi.CATField:
+		if nil != h.OnCAT {
+			h.OnCAT(pid, t)
+		}
+	case *psi.PMTField:
+		if nil != h.OnPMT {
+			h.OnPMT(pid, t)
+		}
+	case *psi.NITField:
+		if nil != h.OnNIT {
+			h.OnNIT(pid, t)
+		}
+	case *psi.SDTField:
+		if nil != h.OnSDT {
+			h.OnSDT(pid, t)
+		}
+	case *psi.BATField:
+		if nil != h.OnBAT {
+			h.OnBAT(pid, t)
+		}
+	case *psi.EITField:
+		if nil != h.OnEIT {
+			h.OnEIT(pid, t)
+		}
+	case *psi.TDTField:
+		if nil != h.OnTDT {
+			h.OnTDT(pid, t)
+		}
+	case *psi.TOTField:
+		if nil != h.OnTOT {
+			h.OnTOT(pid, t)
+		}
+	}
+
+	if nil != h.OnTable {
+		h.OnTable(pid, table)
+	}
+}
+
+// Report err to OnError. If OnError is nil, err is returned.
+func (h *Handler) report(err error) error {
+	if nil == h.OnError {
+		return err
+	}
+	h.OnError(err)
+	return nil
+}
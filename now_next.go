@@ -0,0 +1,127 @@
+package mpeg2ts
+
+import (
+	"mpeg2ts/psi"
+)
+
+type (
+	// NowNextTracker tracks present and following events of each service by EIT present/following
+	// of actual TS. (table_id 0x4E, section_number 0 is present and 1 is following)
+	// Each callback is optional. (nil is ignored)
+	NowNextTracker struct {
+		// OnChange is called when event_id of present or following event of service is changed.
+		// (e.g. programme boundary)
+		OnChange func(change NowNextChange)
+
+		// OnUpdate is called with present and following events whenever section of present/following is received.
+		// Events are nil when they are not received or there is no event. (e.g. to follow overrun by start_time and duration)
+		OnUpdate func(service ServiceKey, present *psi.EITEvent, following *psi.EITEvent)
+
+		services map[ServiceKey]*nowNext
+	}
+
+	// NowNextChange has events around programme boundary.
+	// Previous is present event before the change. (same as Present when only following event is changed)
+	// Events are nil when there is no event. (e.g. first section, or no programme)
+	NowNextChange struct {
+		Service   ServiceKey
+		Previous  *psi.EITEvent
+		Present   *psi.EITEvent
+		Following *psi.EITEvent
+	}
+
+	nowNext struct {
+		present   *psi.EITEvent
+		following *psi.EITEvent
+		// Whether sections of present and following event are received.
+		hasPresent   bool
+		hasFollowing bool
+	}
+)
+
+const (
+	EIT_PRESENT_SECTION_NUMBER   = 0
+	EIT_FOLLOWING_SECTION_NUMBER = 1
+)
+
+func NewNowNextTracker() *NowNextTracker {
+	return &NowNextTracker{
+		services: map[ServiceKey]*nowNext{},
+	}
+}
+
+// Push updates present or following event of service by EIT section.
+// Sections which are not present/following of actual TS are ignored.
+func (tracker *NowNextTracker) Push(eit *psi.EITField) {
+	if nil == eit || !eit.NextIndicator || psi.EIT_PF_ACTUAL_TABLE_ID != eit.TableId {
+		return
+	}
+	key := ServiceKey{
+		OriginalNetworkId: eit.OriginalNetworkId,
+		TransportStreamId: eit.TransportStreamId,
+		ServiceId:         eit.ServiceId,
+	}
+	service, ok := tracker.services[key]
+	if !ok {
+		service = &nowNext{}
+		tracker.services[key] = service
+	}
+
+	var event *psi.EITEvent
+	if 0 < len(eit.Events) {
+		e := eit.Events[0]
+		event = &e
+	}
+
+	previous := service.present
+	changed := false
+	switch eit.SectionNumber {
+	case EIT_PRESENT_SECTION_NUMBER:
+		changed = !service.hasPresent || !isSameEvent(service.present, event)
+		service.present = event
+		service.hasPresent = true
+	case EIT_FOLLOWING_SECTION_NUMBER:
+		changed = !service.hasFollowing || !isSameEvent(service.following, event)
+		service.following = event
+		service.hasFollowing = true
+	default:
+		return
+	}
+
+	if changed && nil != tracker.OnChange {
+		tracker.OnChange(NowNextChange{
+			Service:   key,
+			Previous:  previous,
+			Present:   service.present,
+			Following: service.following,
+		})
+	}
+	if nil != tracker.OnUpdate {
+		tracker.OnUpdate(key, service.present, service.following)
+	}
+}
+
+// Present returns present event of service.
+func (tracker *NowNextTracker) Present(key ServiceKey) (psi.EITEvent, bool) {
+	service, ok := tracker.services[key]
+	if !ok || nil == service.present {
+		return psi.EITEvent{}, false
+	}
+	return *service.present, true
+}
+
+// Following returns following event of service.
+func (tracker *NowNextTracker) Following(key ServiceKey) (psi.EITEvent, bool) {
+	service, ok := tracker.services[key]
+	if !ok || nil == service.following {
+		return psi.EITEvent{}, false
+	}
+	return *service.following, true
+}
+
+func isSameEvent(a *psi.EITEvent, b *psi.EITEvent) bool {
+	if nil == a || nil == b {
+		return a == b
+	}
+	return a.EventId == b.EventId
+}
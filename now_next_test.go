@@ -0,0 +1,150 @@
+package mpeg2ts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mpeg2ts/psi"
+)
+
+// Returns present (section_number 0) or following (section_number 1) section of testServiceKey.
+func newTestPf(version byte, sectionNumber byte, events ...psi.EITEvent) *psi.EITField {
+	return newTestEit(eitSection{psi.EIT_PF_ACTUAL_TABLE_ID, version, sectionNumber, 1, 1, psi.EIT_PF_ACTUAL_TABLE_ID}, events...)
+}
+
+func eventId(event *psi.EITEvent) int {
+	if nil == event {
+		return -1
+	}
+	return int(event.EventId)
+}
+
+func TestNowNextTrackerPush(t *testing.T) {
+	first := newTestEvent(1, 10, 0, time.Hour)
+	overrun := newTestEvent(1, 10, 0, 90*time.Minute)
+	second := newTestEvent(2, 11, 0, time.Hour)
+	third := newTestEvent(3, 12, 0, time.Hour)
+
+	// -1 means no event.
+	cases := []struct {
+		name      string
+		sections  []*psi.EITField
+		changes   [][3]int // event_id of previous, present and following
+		updates   [][2]int // event_id of present and following
+		present   int
+		following int
+	}{
+		{
+			name:     "first sections",
+			sections: []*psi.EITField{newTestPf(0, 0, first), newTestPf(0, 1, second)},
+			changes:  [][3]int{{-1, 1, -1}, {1, 1, 2}},
+			updates:  [][2]int{{1, -1}, {1, 2}},
+			present:  1, following: 2,
+		},
+		{
+			name: "repeated sections",
+			sections: []*psi.EITField{
+				newTestPf(0, 0, first), newTestPf(0, 1, second),
+				newTestPf(0, 0, first), newTestPf(0, 1, second),
+			},
+			changes: [][3]int{{-1, 1, -1}, {1, 1, 2}},
+			updates: [][2]int{{1, -1}, {1, 2}, {1, 2}, {1, 2}},
+			present: 1, following: 2,
+		},
+		{
+			name: "overrun",
+			sections: []*psi.EITField{
+				newTestPf(0, 0, first), newTestPf(0, 1, second),
+				newTestPf(1, 0, overrun),
+			},
+			changes: [][3]int{{-1, 1, -1}, {1, 1, 2}},
+			updates: [][2]int{{1, -1}, {1, 2}, {1, 2}},
+			present: 1, following: 2,
+		},
+		{
+			name: "version change",
+			sections: []*psi.EITField{
+				newTestPf(0, 0, first), newTestPf(0, 1, second),
+				newTestPf(1, 0, second), newTestPf(1, 1, third),
+			},
+			changes: [][3]int{{-1, 1, -1}, {1, 1, 2}, {1, 2, 2}, {2, 2, 3}},
+			updates: [][2]int{{1, -1}, {1, 2}, {2, 2}, {2, 3}},
+			present: 2, following: 3,
+		},
+		{
+			name: "no programme",
+			sections: []*psi.EITField{
+				newTestPf(0, 0, first), newTestPf(0, 1, second),
+				newTestPf(1, 0), newTestPf(1, 1),
+			},
+			changes: [][3]int{{-1, 1, -1}, {1, 1, 2}, {1, -1, 2}, {-1, -1, -1}},
+			updates: [][2]int{{1, -1}, {1, 2}, {-1, 2}, {-1, -1}},
+			present: -1, following: -1,
+		},
+		{
+			name: "not applicable section",
+			sections: []*psi.EITField{
+				newTestPf(0, 0, first),
+				func() *psi.EITField {
+					eit := newTestPf(1, 0, second)
+					eit.NextIndicator = false
+					return eit
+				}(),
+				newTestEit(eitSection{psi.EIT_PF_OTHER_TABLE_ID, 0, 0, 1, 1, psi.EIT_PF_OTHER_TABLE_ID}, third),
+			},
+			changes: [][3]int{{-1, 1, -1}},
+			updates: [][2]int{{1, -1}},
+			present: 1, following: -1,
+		},
+	}
+	for _, c := range cases {
+		changes := [][3]int{}
+		updates := [][2]int{}
+		tracker := NewNowNextTracker()
+		tracker.OnChange = func(change NowNextChange) {
+			if testServiceKey != change.Service {
+				t.Errorf("%s: OnChange is called with service %+v", c.name, change.Service)
+			}
+			changes = append(changes, [3]int{eventId(change.Previous), eventId(change.Present), eventId(change.Following)})
+		}
+		tracker.OnUpdate = func(service ServiceKey, present *psi.EITEvent, following *psi.EITEvent) {
+			updates = append(updates, [2]int{eventId(present), eventId(following)})
+		}
+		for _, eit := range c.sections {
+			tracker.Push(eit)
+		}
+		if !reflect.DeepEqual(c.changes, changes) {
+			t.Errorf("%s: OnChange is called with %v, want %v", c.name, changes, c.changes)
+		}
+		if !reflect.DeepEqual(c.updates, updates) {
+			t.Errorf("%s: OnUpdate is called with %v, want %v", c.name, updates, c.updates)
+		}
+
+		present, ok := tracker.Present(testServiceKey)
+		if (-1 != c.present) != ok || (ok && uint16(c.present) != present.EventId) {
+			t.Errorf("%s: Present returns %d and %v, want %d", c.name, present.EventId, ok, c.present)
+		}
+		following, ok := tracker.Following(testServiceKey)
+		if (-1 != c.following) != ok || (ok && uint16(c.following) != following.EventId) {
+			t.Errorf("%s: Following returns %d and %v, want %d", c.name, following.EventId, ok, c.following)
+		}
+	}
+}
+
+func TestNowNextTrackerOverrun(t *testing.T) {
+	var durations []time.Duration
+	tracker := NewNowNextTracker()
+	tracker.OnUpdate = func(service ServiceKey, present *psi.EITEvent, following *psi.EITEvent) {
+		durations = append(durations, present.Duration)
+	}
+	tracker.Push(newTestPf(0, 0, newTestEvent(1, 10, 0, time.Hour)))
+	tracker.Push(newTestPf(1, 0, newTestEvent(1, 10, 0, 90*time.Minute)))
+
+	if want := []time.Duration{time.Hour, 90 * time.Minute}; !reflect.DeepEqual(want, durations) {
+		t.Errorf("OnUpdate is called with durations %v, want %v", durations, want)
+	}
+	if present, _ := tracker.Present(testServiceKey); 90*time.Minute != present.Duration {
+		t.Errorf("duration of present event is %v, want %v", present.Duration, 90*time.Minute)
+	}
+}
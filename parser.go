@@ -1,89 +1,342 @@
 package mpeg2ts
 
 import (
-	"bufio"
-	"fmt"
+	"context"
+	"io"
 	"os"
+	"time"
 
 	"mpeg2ts/psi"
 )
 
 type (
 	Parser struct {
-		PayloadBuffers map[uint]([]byte)
+		// PacketSize is PACKET_SIZE, M2TS_PACKET_SIZE or RS_PACKET_SIZE.
+		// 0 means auto detection.
+		PacketSize int
+
+		// If DeliverBrokenSections is true, sections which have CRC error are delivered after the error is reported.
+		// Otherwise, these sections are dropped.
+		// NOTE broken sections are only delivered to Handler. (they do not update registered PIDs and Clock)
+		DeliverBrokenSections bool
+
+		// ClockProgramNumber selects program whose PCR_PID is used by Clock.
+		// 0 means the program of first received PMT.
+		ClockProgramNumber uint16
+
+		Handler Handler
+
+		continuity *ContinuityChecker
+		assemblers map[uint]*SectionAssembler
+		// Count of CRC errors by PID and table_id.
+		crcErrors map[uint]map[byte]uint64
+		cache     *TableCache
+		clock     *Clock
+		// Program whose PCR_PID is set to clock.
+		clockProgram    uint16
+		hasClockProgram bool
+
+		// Table functions of each PID.
+		tables map[uint]psi.TableFunc
+		// PIDs which are registered by PAT, and current PAT sections.
+		patPids     map[uint]struct{}
+		patSections map[byte]*psi.PATField
 	}
 )
 
 func NewParser() (p *Parser) {
 	p = &Parser{
-		PayloadBuffers: map[uint]([]byte){},
+		continuity:  NewContinuityChecker(),
+		assemblers:  map[uint]*SectionAssembler{},
+		crcErrors:   map[uint]map[byte]uint64{},
+		cache:       NewTableCache(),
+		clock:       NewClock(),
+		tables:      psi.NewFunctionTables(psi.JST),
+		patPids:     map[uint]struct{}{},
+		patSections: map[byte]*psi.PATField{},
 	}
 	return p
 }
 
+// SetLocation sets time zone in which time fields of well-known tables are decoded. (default is JST)
+// NOTE functions of registered well-known PIDs are replaced.
+// Zone of local time offset descriptor in TOT is not applied. (see psi.TOTField.LocalLocation)
+func (p *Parser) SetLocation(loc *time.Location) {
+	for pid, f := range psi.NewFunctionTables(loc) {
+		if _, ok := p.tables[pid]; ok {
+			p.tables[pid] = f
+		}
+	}
+}
+
+// RegisterPid sets table function f for pid.
+func (p *Parser) RegisterPid(pid uint, f psi.TableFunc) {
+	p.tables[pid] = f
+}
+
+// UnregisterPid removes table function for pid, and cached tables of pid.
+func (p *Parser) UnregisterPid(pid uint) {
+	delete(p.tables, pid)
+	delete(p.assemblers, pid)
+	p.cache.RemovePid(pid)
+}
+
+// Parse parses ts file which is placed at tsPath.
 func (p *Parser) Parse(tsPath string) error {
 	fp, err := os.OpenFile(tsPath, os.O_RDONLY, 0600)
 	if nil != err {
 		return err
 	}
-	reader := bufio.NewReader(fp)
+	defer fp.Close()
+
+	return p.ParseReader(context.Background(), fp)
+}
+
+// ParseReader parses ts stream which is read from reader until EOF or ctx is done.
+func (p *Parser) ParseReader(ctx context.Context, reader io.Reader) error {
+	packetReader, err := NewPacketReader(reader, p.PacketSize)
+	if nil != err {
+		return err
+	}
+	packetReader.OnResync = p.Handler.OnResync
+	p.continuity.OnDrop = p.Handler.OnDrop
 
 	for true {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
 		// Read one packet
-		packetBuffer := make([]byte, PACKET_SIZE)
-		size, readErr := reader.Read(packetBuffer)
+		packetBuffer, offset, readErr := packetReader.ReadPacket()
+		if io.EOF == readErr {
+			break
+		}
+		if io.ErrUnexpectedEOF == readErr {
+			// Last packet is truncated.
+			return p.Handler.report(&ParseError{Pid: NULL_PID, Offset: offset, Err: readErr})
+		}
 		if nil != readErr {
-			if "EOF" == readErr.Error() {
-				break
-			}
 			return readErr
 		}
-		if size == 0 {
-			// Just read finish when read previous packet.
-			break
+
+		packet, parseErr := ParsePacket(packetBuffer)
+		if nil != parseErr {
+			reportErr := p.Handler.report(&ParseError{Pid: NULL_PID, Offset: offset, Err: parseErr})
+			if nil != reportErr {
+				return reportErr
+			}
+			continue
+		}
+		continuity := p.continuity.Check(packet, offset)
+		if !packet.TransportErrorIndicator {
+			p.clock.UpdatePCR(packet.Pid, packet.Adaptation)
+		}
+
+		// Only PIDs which have table function are assembled.
+		f, ok := p.tables[packet.Pid]
+		if !ok || !packet.HavePayload() {
+			continue
+		}
+		assembler, ok := p.assemblers[packet.Pid]
+		if !ok {
+			assembler = NewSectionAssembler()
+			p.assemblers[packet.Pid] = assembler
+		}
+		if packet.TransportErrorIndicator || CONTINUITY_DROPPED == continuity {
+			assembler.Reset()
+		}
+		if packet.TransportErrorIndicator || CONTINUITY_DUPLICATED == continuity {
+			continue
 		}
-		if size != PACKET_SIZE {
-			// NOTE bufio.Reader's readable size is not full file size(usually, maybe about 4k byte.).
-			// So, if read size is not enought array size, we need once retry reading.
-			remainBytes := packetBuffer[size:PACKET_SIZE]
-			remainSize, err := reader.Read(remainBytes)
+
+		sections, assembleErr := assembler.Push(packet.Payload, packet.PayloadUnitStartIndicator)
+		if nil != assembleErr {
+			reportErr := p.Handler.report(&ParseError{Pid: packet.Pid, Offset: offset, Err: assembleErr})
+			if nil != reportErr {
+				return reportErr
+			}
+		}
+		for _, section := range sections {
+			err = p.parseSection(packet.Pid, offset, f, section)
 			if nil != err {
 				return err
 			}
-			if PACKET_SIZE != remainSize+size {
-				return fmt.Errorf("Not enought packet data readed (%dbyte :expect %dbyte)", remainSize+size, PACKET_SIZE)
+		}
+	}
+	return nil
+}
+
+// Parse section by table function f, and deliver it to Handler.
+// Sections which have section_syntax_indicator are delivered when all sections of the table are collected,
+// and repeated sections are not delivered.
+func (p *Parser) parseSection(pid uint, offset int64, f psi.TableFunc, section []byte) error {
+	broken := false
+	if psi.HasCrc(section) {
+		crcErr := psi.VerifyCrc(section)
+		if nil != crcErr {
+			broken = true
+			if _, ok := p.crcErrors[pid]; !ok {
+				p.crcErrors[pid] = map[byte]uint64{}
+			}
+			p.crcErrors[pid][section[0]]++
+
+			reportErr := p.Handler.report(&ParseError{Pid: pid, Offset: offset, Err: crcErr})
+			if nil != reportErr || !p.DeliverBrokenSections {
+				return reportErr
 			}
 		}
+	}
 
-		packet, parseErr := ParseTsHeader(packetBuffer)
-		if nil != parseErr {
-			return parseErr
-		}
-
-		if packet.PayloadUnitStartIndicator {
-			buffer, ok := p.PayloadBuffers[packet.Pid]
-			if ok {
-				// Parse each type of Pid
-				f, ok := psi.FunctionTables[packet.Pid]
-				if ok {
-					// TODO Manage data
-					_, funcErr := f(buffer)
-					if nil != funcErr {
-						panic(funcErr)
-					}
-				}
+	if !psi.HasSectionSyntax(section) || broken {
+		// Short section and broken section are not cached.
+		table, funcErr := f(section)
+		if nil != funcErr {
+			reportErr := p.Handler.report(&ParseError{Pid: pid, Offset: offset, Err: funcErr})
+			if nil != reportErr {
+				return reportErr
+			}
+		}
+		if broken {
+			// NOTE broken section is only dispatched to Handler, so that it does not update program map and clock.
+			if nil != table {
+				p.Handler.dispatch(pid, table)
 			}
+			return nil
+		}
+		p.deliver(pid, table)
+		return nil
+	}
+
+	header, headerErr := psi.ParseExtendedHeader(section)
+	if nil != headerErr {
+		return p.Handler.report(&ParseError{Pid: pid, Offset: offset, Err: headerErr})
+	}
+	if !header.CurrentNextIndicator {
+		// Not applicable yet.
+		return nil
+	}
+	key := NewTableKey(pid, section, header)
+	if p.cache.IsRepeated(key, header) {
+		return nil
+	}
+
+	// NOTE partially broken table is reported and delivered.
+	table, funcErr := f(section)
+	if nil != funcErr {
+		reportErr := p.Handler.report(&ParseError{Pid: pid, Offset: offset, Err: funcErr})
+		if nil != reportErr {
+			return reportErr
+		}
+	}
+	if nil == table {
+		return nil
+	}
+	tables, change := p.cache.Push(key, header, table)
+	for _, t := range tables {
+		p.deliver(pid, t)
+	}
+	if nil != change && nil != p.Handler.OnTableChange {
+		p.Handler.OnTableChange(*change)
+	}
+	return nil
+}
+
+func (p *Parser) deliver(pid uint, table interface{}) {
+	if nil == table {
+		return
+	}
+	switch t := table.(type) {
+	case *psi.PATField:
+		p.updateProgramMap(t)
+	case *psi.PMTField:
+		p.updatePcrPid(t)
+	case *psi.TDTField:
+		p.clock.UpdateTime(t.Time)
+	case *psi.TOTField:
+		p.clock.UpdateTime(t.Time)
+	}
+	p.Handler.dispatch(pid, table)
+}
+
+// Register PMT's PIDs and network PID listed in current PAT, and unregister PIDs which are removed from PAT.
+func (p *Parser) updateProgramMap(pat *psi.PATField) {
+	if !pat.CurrentNextIndicator {
+		return
+	}
+	for _, section := range p.patSections {
+		if section.VersionNumber != pat.VersionNumber || section.TransportStreamId != pat.TransportStreamId {
+			p.patSections = map[byte]*psi.PATField{}
+			break
+		}
+	}
+	p.patSections[pat.SectionNumber] = pat
 
-			// Set new packet
-			p.PayloadBuffers[packet.Pid] = packet.Payload
-		} else {
-			buffer, ok := p.PayloadBuffers[packet.Pid]
-			if ok {
-				p.PayloadBuffers[packet.Pid] = append(buffer, packet.Payload...)
+	pids := map[uint]psi.TableFunc{}
+	for _, section := range p.patSections {
+		for _, pa := range section.ProgramAssociations {
+			if psi.NETWORK_PROGRAM_NUMBER == pa.ProgramNumber {
+				pids[pa.Pid] = psi.ParseNit
 			} else {
-				//return fmt.Errorf("0x%X packet is not first, but buffer is not found.", packet.Pid)
+				pids[pa.Pid] = psi.ParsePmt
 			}
 		}
 	}
-	return nil
+
+	for pid := range p.patPids {
+		if _, ok := pids[pid]; !ok {
+			p.UnregisterPid(pid)
+			delete(p.patPids, pid)
+		}
+	}
+	for pid, f := range pids {
+		if _, ok := p.patPids[pid]; ok {
+			continue
+		}
+		// NOTE well-known PIDs are not overwritten.
+		if _, ok := p.tables[pid]; !ok {
+			p.RegisterPid(pid, f)
+			p.patPids[pid] = struct{}{}
+		}
+	}
+}
+
+// Set PCR_PID of selected program to clock, so that time is mapped by time base of the program.
+func (p *Parser) updatePcrPid(pmt *psi.PMTField) {
+	if !pmt.CurrentNextIndicator || NULL_PID == pmt.PCRPid {
+		return
+	}
+	if 0 != p.ClockProgramNumber && p.ClockProgramNumber != pmt.ProgramNumber {
+		return
+	}
+	if 0 == p.ClockProgramNumber && p.hasClockProgram && p.clockProgram != pmt.ProgramNumber {
+		return
+	}
+	p.clockProgram = pmt.ProgramNumber
+	p.hasClockProgram = true
+	p.clock.SetPcrPid(uint(pmt.PCRPid))
+}
+
+// Clock returns clock which maps PCR to time of TDT/TOT.
+// PCR of PCR_PID in PMT of ClockProgramNumber is used.
+func (p *Parser) Clock() *Clock {
+	return p.clock
+}
+
+// Statistics returns statistics of each PID. (packets, drops, lost packets, errors and scrambled)
+func (p *Parser) Statistics() map[uint]PidStatistics {
+	return p.continuity.Statistics()
+}
+
+// CrcErrors returns count of CRC errors by PID and table_id.
+func (p *Parser) CrcErrors() map[uint]map[byte]uint64 {
+	crcErrors := make(map[uint]map[byte]uint64, len(p.crcErrors))
+	for pid, counts := range p.crcErrors {
+		crcErrors[pid] = make(map[byte]uint64, len(counts))
+		for tableId, count := range counts {
+			crcErrors[pid][tableId] = count
+		}
+	}
+	return crcErrors
 }
@@ -0,0 +1,93 @@
+package mpeg2ts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"mpeg2ts/psi"
+)
+
+// Returns section whose section_length and CRC_32 are filled. (body follows section_length)
+func newTestCrcSection(tableId byte, body []byte) []byte {
+	length := len(body) + psi.CRC_LENGTH
+	section := append([]byte{tableId, 0xB0 | byte(length>>8), byte(length)}, body...)
+	section = append(section, make([]byte, psi.CRC_LENGTH)...)
+	binary.BigEndian.PutUint32(section[len(section)-psi.CRC_LENGTH:], psi.Crc32(section[:len(section)-psi.CRC_LENGTH]))
+	return section
+}
+
+// Returns PAT of transport_stream_id 1 which has program_number 1.
+func newTestPat(version byte, pmtPid uint16) []byte {
+	return newTestCrcSection(psi.PAT_TABLE_ID, []byte{
+		0x00, 0x01, 0xC1 | version<<1, 0x00, 0x00,
+		0x00, 0x01, 0xE0 | byte(pmtPid>>8), byte(pmtPid),
+	})
+}
+
+// Returns PMT of program_number 1 which has one video stream.
+func newTestPmt(version byte, pcrPid uint16) []byte {
+	return newTestCrcSection(psi.PMT_TABLE_ID, []byte{
+		0x00, 0x01, 0xC1 | version<<1, 0x00, 0x00,
+		0xE0 | byte(pcrPid>>8), byte(pcrPid), 0xF0, 0x00,
+		0x02, 0xE1, 0x01, 0xF0, 0x00,
+	})
+}
+
+// Returns packet of pid which has whole section.
+func newTestSectionPacket(pid uint16, counter byte, section []byte) []byte {
+	packet := []byte{SYNC_BYTE, 0x40 | byte(pid>>8), byte(pid), 0x10 | counter&0x0F, 0x00}
+	packet = append(packet, section...)
+	return append(packet, bytes.Repeat([]byte{STUFFING_BYTE}, PACKET_SIZE-len(packet))...)
+}
+
+func TestParserBrokenSection(t *testing.T) {
+	brokenPat := newTestPat(1, 0x0200)
+	brokenPat[len(brokenPat)-1] ^= 0x01
+
+	stream := []byte{}
+	stream = append(stream, newTestSectionPacket(0x0000, 0, newTestPat(0, 0x0100))...)
+	stream = append(stream, newTestSectionPacket(0x0100, 0, newTestPmt(0, 0x0101))...)
+	stream = append(stream, newTestSectionPacket(0x0000, 1, brokenPat)...)
+	stream = append(stream, newTestSectionPacket(0x0100, 1, newTestPmt(1, 0x0102))...)
+
+	cases := []struct {
+		name          string
+		deliverBroken bool
+		pats          int
+		pmtVersions   []byte
+	}{
+		{"drop broken sections", false, 1, []byte{0, 1}},
+		{"deliver broken sections", true, 2, []byte{0, 1}},
+	}
+	for _, c := range cases {
+		pats := 0
+		pmtVersions := []byte{}
+		crcErrors := 0
+		parser := NewParser()
+		parser.DeliverBrokenSections = c.deliverBroken
+		parser.Handler = Handler{
+			OnPAT: func(pid uint, pat *psi.PATField) {
+				pats++
+			},
+			OnPMT: func(pid uint, pmt *psi.PMTField) {
+				pmtVersions = append(pmtVersions, pmt.VersionNumber)
+			},
+			OnError: func(err error) {
+				crcErrors++
+			},
+		}
+		err := parser.ParseReader(context.Background(), bytes.NewReader(stream))
+		if nil != err {
+			t.Fatalf("%s: ParseReader returns %v", c.name, err)
+		}
+		if c.pats != pats || !bytes.Equal(c.pmtVersions, pmtVersions) || 1 != crcErrors {
+			t.Errorf("%s: %d PATs, PMT versions %v and %d errors, want %d, %v and 1",
+				c.name, pats, pmtVersions, crcErrors, c.pats, c.pmtVersions)
+		}
+		if _, ok := parser.tables[0x0200]; ok {
+			t.Errorf("%s: PID of broken PAT is registered", c.name)
+		}
+	}
+}
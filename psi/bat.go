@@ -0,0 +1,97 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	BATField struct {
+		Common
+		BouquetId                 uint
+		reserved2                 byte
+		VersionNumber             byte
+		CurrentNextIndicator      bool
+		SectionNumber             byte
+		LastSectionNumber         byte
+		reserved3                 byte
+		BouquetDescriptorsLength  uint16
+		Descriptors               []interface{}
+		reserved4                 byte
+		TransportStreamLoopLength uint16
+
+		TransportStreams []NITTransportStream
+
+		Crc []byte
+	}
+)
+
+const BAT_FIELD_LENGTH = 7
+
+func ParseBat(buffer []byte) (interface{}, error) {
+	if 0 < len(buffer) && BAT_TABLE_ID != buffer[0] {
+		// Not BAT. (e.g. stuffing table)
+		return nil, nil
+	}
+
+	bat := &BATField{}
+	batBuffer, err := parseSectionHeader(buffer, &bat.Common)
+	if nil != err {
+		return nil, err
+	}
+	if len(batBuffer) < BAT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("BAT section is too short. (section_length is %d)", bat.SectionLength)
+	}
+
+	bat.BouquetId = uint(binary.BigEndian.Uint16(batBuffer[0:2]))
+	bat.reserved2 = batBuffer[2] & 0xC0 >> 6
+	bat.VersionNumber = batBuffer[2] & 0x3E >> 1
+	bat.CurrentNextIndicator = batBuffer[2]&0x01 > 0
+	bat.SectionNumber = batBuffer[3]
+	bat.LastSectionNumber = batBuffer[4]
+	bat.reserved3 = batBuffer[5] & 0xF0 >> 4
+	bat.BouquetDescriptorsLength = binary.BigEndian.Uint16([]byte{batBuffer[5] & 0x0F, batBuffer[6]})
+
+	crcHead := int(bat.SectionLength) - CRC_LENGTH
+	descriptorTail := BAT_FIELD_LENGTH + int(bat.BouquetDescriptorsLength)
+	if crcHead < descriptorTail+2 {
+		return nil, fmt.Errorf("Invalid bouquet_descriptors_length %d. (section_length is %d)", bat.BouquetDescriptorsLength, bat.SectionLength)
+	}
+	// Error of first broken descriptor, which is returned with bat.
+	// NOTE bouquet and transport streams are kept even if some descriptors are broken.
+	var brokenErr error
+	bat.Descriptors, err = ParseDescriptors(batBuffer[BAT_FIELD_LENGTH:descriptorTail])
+	if nil != err {
+		brokenErr = fmt.Errorf("Bouquet 0x%04X has broken descriptor. (%s)", bat.BouquetId, err.Error())
+	}
+
+	bat.reserved4 = batBuffer[descriptorTail] & 0xF0 >> 4
+	bat.TransportStreamLoopLength = binary.BigEndian.Uint16([]byte{batBuffer[descriptorTail] & 0x0F, batBuffer[descriptorTail+1]})
+	loopHead := descriptorTail + 2
+	loopTail := loopHead + int(bat.TransportStreamLoopLength)
+	if crcHead < loopTail {
+		return nil, fmt.Errorf("Invalid transport_stream_loop_length %d. (section_length is %d)", bat.TransportStreamLoopLength, bat.SectionLength)
+	}
+	var streamErr error
+	bat.TransportStreams, streamErr, err = parseTransportStreamLoop(batBuffer[loopHead:loopTail])
+	if nil != err {
+		return nil, err
+	}
+
+	if nil == brokenErr {
+		brokenErr = streamErr
+	}
+
+	bat.Crc = batBuffer[crcHead:bat.SectionLength]
+	return bat, brokenErr
+}
+
+// BouquetName returns name in bouquet name descriptor.
+func (bat *BATField) BouquetName() string {
+	for _, descriptor := range bat.Descriptors {
+		if bnd, ok := descriptor.(BouquetNameDescriptor); ok {
+			return bnd.Name
+		}
+	}
+	return ""
+}
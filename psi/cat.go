@@ -0,0 +1,87 @@
+package psi
+
+import (
+	"fmt"
+)
+
+type (
+	CATField struct {
+		Common
+		reserved2            uint
+		VersionNumber        byte
+		CurrentNextIndicator bool
+		SectionNumber        byte
+		LastSectionNumber    byte
+
+		CADescriptors []CADescriptor // EMM of each CA system
+
+		Crc []byte
+	}
+)
+
+const CAT_FIELD_LENGTH = 5
+
+func ParseCat(buffer []byte) (interface{}, error) {
+	if 0 < len(buffer) && CAT_TABLE_ID != buffer[0] {
+		// Not CAT. (e.g. stuffing table)
+		return nil, nil
+	}
+
+	cat := &CATField{}
+	catBuffer, err := parseSectionHeader(buffer, &cat.Common)
+	if nil != err {
+		return nil, err
+	}
+	if len(catBuffer) < CAT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("CAT section is too short. (section_length is %d)", cat.SectionLength)
+	}
+
+	cat.reserved2 = uint(catBuffer[0])<<10 | uint(catBuffer[1])<<2 | uint(catBuffer[2]&0xC0>>6)
+	cat.VersionNumber = catBuffer[2] & 0x3E >> 1
+	cat.CurrentNextIndicator = catBuffer[2]&0x01 > 0
+	cat.SectionNumber = catBuffer[3]
+	cat.LastSectionNumber = catBuffer[4]
+
+	descriptorTail := int(cat.SectionLength) - CRC_LENGTH
+	descriptorBuffer := catBuffer[CAT_FIELD_LENGTH:descriptorTail]
+	// Error of first broken descriptor, which is returned with cat.
+	var brokenErr error
+	for idx := uint(0); idx < uint(len(descriptorBuffer)); {
+		if CATag != descriptorBuffer[idx] {
+			// Skip other descriptors.
+			if uint(len(descriptorBuffer)) < idx+2 {
+				return nil, fmt.Errorf("Descriptor loop is broken. (%d bytes remain)", uint(len(descriptorBuffer))-idx)
+			}
+			idx += uint(descriptorBuffer[idx+1]) + 2
+			continue
+		}
+		cad, size, err := ParseCADescriptor(descriptorBuffer[idx:])
+		if 0 == size {
+			// Descriptor loop is broken.
+			return nil, err
+		}
+		if nil != err {
+			// NOTE CAT is kept even if some CA descriptors are broken.
+			if nil == brokenErr {
+				brokenErr = &DescriptorError{Tag: CATag, Err: err}
+			}
+			idx += size
+			continue
+		}
+		cat.CADescriptors = append(cat.CADescriptors, cad)
+		idx += size
+	}
+
+	cat.Crc = catBuffer[descriptorTail:cat.SectionLength]
+	return cat, brokenErr
+}
+
+// EmmPids returns EMM PIDs by CA_system_id in order of CA descriptors.
+// NOTE a CA system may have several EMM PIDs.
+func (cat *CATField) EmmPids() map[uint16][]uint16 {
+	pids := map[uint16][]uint16{}
+	for _, cad := range cat.CADescriptors {
+		pids[cad.CASystemId] = append(pids[cad.CASystemId], cad.CAPid)
+	}
+	return pids
+}
@@ -0,0 +1,48 @@
+package psi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCat(t *testing.T) {
+	header := []byte{0xFF, 0xFF, 0xC1, 0x00, 0x00}
+
+	cases := []struct {
+		name        string
+		descriptors []byte
+		emmPids     map[uint16][]uint16
+		err         bool
+	}{
+		{
+			name:        "EMM PIDs of CA systems",
+			descriptors: []byte{CATag, 0x04, 0x00, 0x05, 0xE0, 0x10, CATag, 0x04, 0x00, 0x05, 0xE0, 0x11, CATag, 0x04, 0x00, 0x0A, 0xE0, 0x12},
+			emmPids:     map[uint16][]uint16{0x0005: {0x0010, 0x0011}, 0x000A: {0x0012}},
+		},
+		{
+			name:        "other descriptor",
+			descriptors: []byte{0xC1, 0x01, 0x00, CATag, 0x04, 0x00, 0x05, 0xE0, 0x10},
+			emmPids:     map[uint16][]uint16{0x0005: {0x0010}},
+		},
+		{
+			name:        "short CA descriptor",
+			descriptors: []byte{CATag, 0x02, 0x00, 0x05, CATag, 0x04, 0x00, 0x05, 0xE0, 0x10},
+			emmPids:     map[uint16][]uint16{0x0005: {0x0010}},
+			err:         true,
+		},
+	}
+	for _, c := range cases {
+		table, err := ParseCat(newTestSection(CAT_TABLE_ID, append(append([]byte{}, header...), c.descriptors...)))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParseCat returns %v", c.name, err)
+		}
+		cat, ok := table.(*CATField)
+		if !ok {
+			t.Errorf("%s: ParseCat returns %T, want *CATField", c.name, table)
+			continue
+		}
+		if emmPids := cat.EmmPids(); !reflect.DeepEqual(c.emmPids, emmPids) {
+			t.Errorf("%s: EmmPids returns %v, want %v", c.name, emmPids, c.emmPids)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package psi
+
+import (
+	"fmt"
+)
+
+type (
+	// Descriptor Tag Number : 0x50
+	ComponentDescriptor struct {
+		DescriptorCommon
+		reserved      byte
+		StreamContent byte
+		ComponentType byte
+		ComponentTag  byte
+		LanguageCode  string
+		Text          string
+	}
+
+	// Descriptor Tag Number : 0xC4
+	// LanguageCode2 is set when ESMultiLingualFlag is true. (e.g. bilingual)
+	AudioComponentDescriptor struct {
+		DescriptorCommon
+		reserved           byte
+		StreamContent      byte
+		ComponentType      byte
+		ComponentTag       byte
+		StreamType         byte
+		SimulcastGroupTag  byte
+		ESMultiLingualFlag bool
+		MainComponentFlag  bool
+		QualityIndicator   byte
+		SamplingRate       byte
+		reserved2          byte
+		LanguageCode       string
+		LanguageCode2      string
+		Text               string
+	}
+
+	// Video format which is described by component_type.
+	VideoFormat struct {
+		Resolution  string // e.g. "1080i"
+		Lines       uint
+		Progressive bool
+		AspectRatio string // "4:3", "16:9" or ">16:9"
+		PanVector   bool
+		FrameRate   float64 // frames per second. 0 means unknown.
+	}
+)
+
+const (
+	ComponentTag      = 0x50
+	AudioComponentTag = 0xC4
+
+	COMPONENT_FIELD_LENGTH       = 6
+	AUDIO_COMPONENT_FIELD_LENGTH = 9
+
+	// stream_content
+	STREAM_CONTENT_MPEG2_VIDEO = 0x01
+	STREAM_CONTENT_AUDIO       = 0x02
+	STREAM_CONTENT_H264_VIDEO  = 0x05
+	STREAM_CONTENT_H265_VIDEO  = 0x09
+
+	// component_type of audio
+	AUDIO_MONO      = 0x01
+	AUDIO_DUAL_MONO = 0x02
+	AUDIO_STEREO    = 0x03
+)
+
+// Key is upper nibble of video component_type.
+// REF ARIB STD-B10 第2部 6.2.3
+var videoResolutions = map[byte]VideoFormat{
+	0x0: {Resolution: "480i", Lines: 480, FrameRate: 30000.0 / 1001},
+	0x9: {Resolution: "2160p", Lines: 2160, Progressive: true, FrameRate: 60000.0 / 1001},
+	0xA: {Resolution: "480p", Lines: 480, Progressive: true, FrameRate: 60000.0 / 1001},
+	0xB: {Resolution: "1080i", Lines: 1080, FrameRate: 30000.0 / 1001},
+	0xC: {Resolution: "720p", Lines: 720, Progressive: true, FrameRate: 60000.0 / 1001},
+	0xD: {Resolution: "240p", Lines: 240, Progressive: true},
+	0xE: {Resolution: "1080p", Lines: 1080, Progressive: true, FrameRate: 60000.0 / 1001},
+	0xF: {Resolution: "180p", Lines: 180, Progressive: true},
+}
+
+// Key is audio component_type.
+var audioModes = map[byte]string{
+	AUDIO_MONO:      "mono",
+	AUDIO_DUAL_MONO: "dual mono",
+	AUDIO_STEREO:    "stereo",
+	0x04:            "2/1",
+	0x05:            "3/0",
+	0x06:            "2/2",
+	0x07:            "3/1",
+	0x08:            "3/2",
+	0x09:            "5.1ch",
+	0x0C:            "7.1ch",
+	0x11:            "22.2ch",
+	0x40:            "audio description",
+	0x41:            "hearing impaired",
+}
+
+// Key is sampling_rate.
+var samplingRates = map[byte]uint{
+	0x1: 16000,
+	0x2: 22050,
+	0x3: 24000,
+	0x5: 32000,
+	0x6: 44100,
+	0x7: 48000,
+}
+
+func parseComponentDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < COMPONENT_FIELD_LENGTH {
+		return nil, fmt.Errorf("Component descriptor is too short. (%d bytes)", len(data))
+	}
+	cd := ComponentDescriptor{
+		DescriptorCommon: common,
+		reserved:         data[0] & 0xF0 >> 4,
+		StreamContent:    data[0] & 0x0F,
+		ComponentType:    data[1],
+		ComponentTag:     data[2],
+		LanguageCode:     string(data[3:6]),
+		Text:             decodeString(data[COMPONENT_FIELD_LENGTH:]),
+	}
+	return cd, nil
+}
+
+func parseAudioComponentDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < AUDIO_COMPONENT_FIELD_LENGTH {
+		return nil, fmt.Errorf("Audio component descriptor is too short. (%d bytes)", len(data))
+	}
+	acd := AudioComponentDescriptor{
+		DescriptorCommon:   common,
+		reserved:           data[0] & 0xF0 >> 4,
+		StreamContent:      data[0] & 0x0F,
+		ComponentType:      data[1],
+		ComponentTag:       data[2],
+		StreamType:         data[3],
+		SimulcastGroupTag:  data[4],
+		ESMultiLingualFlag: data[5]&0x80 > 0,
+		MainComponentFlag:  data[5]&0x40 > 0,
+		QualityIndicator:   data[5] & 0x30 >> 4,
+		SamplingRate:       data[5] & 0x0E >> 1,
+		reserved2:          data[5] & 0x01,
+		LanguageCode:       string(data[6:9]),
+	}
+	textHead := AUDIO_COMPONENT_FIELD_LENGTH
+	if acd.ESMultiLingualFlag {
+		textHead += 3
+		if len(data) < textHead {
+			return nil, fmt.Errorf("Audio component descriptor is too short for second language. (%d bytes)", len(data))
+		}
+		acd.LanguageCode2 = string(data[9:12])
+	}
+	acd.Text = decodeString(data[textHead:])
+	return acd, nil
+}
+
+// IsVideo returns whether component is video stream.
+func (cd ComponentDescriptor) IsVideo() bool {
+	switch cd.StreamContent {
+	case STREAM_CONTENT_MPEG2_VIDEO, STREAM_CONTENT_H264_VIDEO, STREAM_CONTENT_H265_VIDEO:
+		return true
+	}
+	return false
+}
+
+// VideoFormat returns format of video component. It returns false if component is not video or unknown type.
+func (cd ComponentDescriptor) VideoFormat() (VideoFormat, bool) {
+	if !cd.IsVideo() {
+		return VideoFormat{}, false
+	}
+	format, ok := videoResolutions[cd.ComponentType&0xF0>>4]
+	if !ok {
+		return VideoFormat{}, false
+	}
+	switch cd.ComponentType & 0x0F {
+	case 0x1:
+		format.AspectRatio = "4:3"
+	case 0x2:
+		format.AspectRatio = "16:9"
+		format.PanVector = true
+	case 0x3:
+		format.AspectRatio = "16:9"
+	case 0x4:
+		format.AspectRatio = ">16:9"
+	default:
+		return VideoFormat{}, false
+	}
+	return format, true
+}
+
+// AudioMode returns audio mode of audio component. (e.g. "stereo", "5.1ch")
+// It returns empty string if component is not audio or unknown type.
+func (cd ComponentDescriptor) AudioMode() string {
+	if STREAM_CONTENT_AUDIO != cd.StreamContent {
+		return ""
+	}
+	return audioModes[cd.ComponentType]
+}
+
+// AudioMode returns audio mode. (e.g. "stereo", "5.1ch")
+func (acd AudioComponentDescriptor) AudioMode() string {
+	return audioModes[acd.ComponentType]
+}
+
+// SamplingRateHz returns sampling rate in Hz. 0 means reserved value.
+func (acd AudioComponentDescriptor) SamplingRateHz() uint {
+	return samplingRates[acd.SamplingRate]
+}
+
+// IsBilingual returns whether audio has two languages. (dual mono or multi lingual ES)
+func (acd AudioComponentDescriptor) IsBilingual() bool {
+	return AUDIO_DUAL_MONO == acd.ComponentType || acd.ESMultiLingualFlag
+}
@@ -0,0 +1,26 @@
+package psi
+
+import (
+	"testing"
+)
+
+func TestAudioComponentIsBilingual(t *testing.T) {
+	cases := []struct {
+		name      string
+		data      []byte
+		bilingual bool
+	}{
+		{"stereo", []byte{0xF2, AUDIO_STEREO, 0x10, 0x0F, 0xFF, 0x3F, 'j', 'p', 'n'}, false},
+		{"dual mono", []byte{0xF2, AUDIO_DUAL_MONO, 0x10, 0x0F, 0xFF, 0x3F, 'j', 'p', 'n'}, true},
+		{"multi lingual ES", []byte{0xF2, AUDIO_STEREO, 0x10, 0x0F, 0xFF, 0xBF, 'j', 'p', 'n', 'e', 'n', 'g'}, true},
+	}
+	for _, c := range cases {
+		acd, err := parseAudioComponentDescriptor(DescriptorCommon{Tag: AudioComponentTag, Length: byte(len(c.data))}, c.data)
+		if nil != err {
+			t.Fatalf("%s: parseAudioComponentDescriptor returns %v", c.name, err)
+		}
+		if bilingual := acd.(AudioComponentDescriptor).IsBilingual(); c.bilingual != bilingual {
+			t.Errorf("%s: IsBilingual returns %v, want %v", c.name, bilingual, c.bilingual)
+		}
+	}
+}
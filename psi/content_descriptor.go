@@ -0,0 +1,138 @@
+package psi
+
+import (
+	"fmt"
+)
+
+type (
+	// Descriptor Tag Number : 0x54
+	ContentDescriptor struct {
+		DescriptorCommon
+		Contents []Content
+	}
+
+	Content struct {
+		ContentNibbleLevel1 byte
+		ContentNibbleLevel2 byte
+		UserNibble1         byte
+		UserNibble2         byte
+	}
+
+	// Descriptor Tag Number : 0x55
+	ParentalRatingDescriptor struct {
+		DescriptorCommon
+		Ratings []ParentalRating
+	}
+
+	ParentalRating struct {
+		CountryCode string
+		Rating      byte
+	}
+
+	// Names of large and middle genre. Empty name means undefined or reserved genre.
+	Genre struct {
+		Large  string
+		Middle string
+	}
+)
+
+const (
+	ContentTag        = 0x54
+	ParentalRatingTag = 0x55
+
+	CONTENT_LENGTH         = 2
+	PARENTAL_RATING_LENGTH = 4
+
+	LANGUAGE_ENG = "eng"
+)
+
+func parseContentDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if 0 != len(data)%CONTENT_LENGTH {
+		return nil, fmt.Errorf("Invalid content descriptor length %d.", len(data))
+	}
+	cd := ContentDescriptor{
+		DescriptorCommon: common,
+		Contents:         make([]Content, 0, len(data)/CONTENT_LENGTH),
+	}
+	for idx := 0; idx < len(data); idx += CONTENT_LENGTH {
+		content := Content{
+			ContentNibbleLevel1: data[idx] & 0xF0 >> 4,
+			ContentNibbleLevel2: data[idx] & 0x0F,
+			UserNibble1:         data[idx+1] & 0xF0 >> 4,
+			UserNibble2:         data[idx+1] & 0x0F,
+		}
+		cd.Contents = append(cd.Contents, content)
+	}
+	return cd, nil
+}
+
+// IsAribExtension returns true if genre is described by user_nibble.
+func (content Content) IsAribExtension() bool {
+	return ARIB_GENRE_EXTENSION == content.ContentNibbleLevel1
+}
+
+// AribGenre returns genre names of ARIB STD-B10 in language. (LANGUAGE_JPN or LANGUAGE_ENG)
+// Extension genre returns names of user_nibble's genre.
+// REF ARIB STD-B10 第2部 付録H
+func (content Content) AribGenre(language string) Genre {
+	nameIdx := 1
+	if LANGUAGE_JPN == language {
+		nameIdx = 0
+	}
+
+	if !content.IsAribExtension() {
+		return Genre{
+			Large:  aribLargeGenres[content.ContentNibbleLevel1][nameIdx],
+			Middle: aribMiddleGenres[content.ContentNibbleLevel1<<4|content.ContentNibbleLevel2][nameIdx],
+		}
+	}
+
+	switch content.ContentNibbleLevel2 {
+	case ARIB_PROGRAM_ATTACHMENT:
+		return Genre{
+			Large:  aribExtensionGenres[content.ContentNibbleLevel2][nameIdx],
+			Middle: aribProgramAttachments[content.UserNibble1<<4|content.UserNibble2][nameIdx],
+		}
+	case ARIB_CS_EXTENSION:
+		return Genre{
+			Large:  aribCSLargeGenres[content.UserNibble1][nameIdx],
+			Middle: aribCSMiddleGenres[content.UserNibble1<<4|content.UserNibble2][nameIdx],
+		}
+	}
+	return Genre{Large: aribExtensionGenres[content.ContentNibbleLevel2][nameIdx]}
+}
+
+// DvbGenre returns genre names of EN 300 468.
+// REF ETSI EN 300 468 Table 29
+func (content Content) DvbGenre() Genre {
+	return Genre{
+		Large:  dvbLargeGenres[content.ContentNibbleLevel1],
+		Middle: dvbMiddleGenres[content.ContentNibbleLevel1<<4|content.ContentNibbleLevel2],
+	}
+}
+
+// REF ETSI EN 300 468 6.2.28
+func parseParentalRatingDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if 0 != len(data)%PARENTAL_RATING_LENGTH {
+		return nil, fmt.Errorf("Invalid parental rating descriptor length %d.", len(data))
+	}
+	prd := ParentalRatingDescriptor{
+		DescriptorCommon: common,
+	}
+	for idx := 0; idx < len(data); idx += PARENTAL_RATING_LENGTH {
+		rating := ParentalRating{
+			CountryCode: string(data[idx : idx+3]),
+			Rating:      data[idx+3],
+		}
+		prd.Ratings = append(prd.Ratings, rating)
+	}
+	return prd, nil
+}
+
+// MinimumAge returns minimum age of rating. It returns 0 if rating is undefined or defined by broadcaster.
+func (rating ParentalRating) MinimumAge() uint {
+	if 0x01 <= rating.Rating && 0x0F >= rating.Rating {
+		return uint(rating.Rating) + 3
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	// CrcError is occurred when CRC_32 of section is not matched.
+	CrcError struct {
+		TableId    byte
+		Expected   uint32 // CRC_32 field in section
+		Calculated uint32
+	}
+)
+
+// CRC-32/MPEG-2 (polynomial 0x04C11DB7, not reflected, initial value 0xFFFFFFFF)
+const CRC_POLYNOMIAL = 0x04C11DB7
+
+var crcTable [256]uint32
+
+func init() {
+	for idx := range crcTable {
+		crc := uint32(idx) << 24
+		for bit := 0; bit < 8; bit++ {
+			if 0 != crc&0x80000000 {
+				crc = (crc << 1) ^ CRC_POLYNOMIAL
+			} else {
+				crc <<= 1
+			}
+		}
+		crcTable[idx] = crc
+	}
+}
+
+func (e *CrcError) Error() string {
+	return fmt.Sprintf("CRC_32 is not matched in table_id 0x%02X. (section has 0x%08X but calculated 0x%08X)", e.TableId, e.Expected, e.Calculated)
+}
+
+// Crc32 calculates CRC-32/MPEG-2 of buffer.
+func Crc32(buffer []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range buffer {
+		crc = (crc << 8) ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// HasCrc returns whether section has CRC_32 at the end.
+// NOTE TOT has CRC_32 though it does not have section_syntax_indicator.
+func HasCrc(section []byte) bool {
+	if 0 < len(section) && TOT_TABLE_ID == section[0] {
+		return true
+	}
+	return HasSectionSyntax(section)
+}
+
+// VerifyCrc checks CRC_32 at the end of section. It returns *CrcError when CRC is not matched.
+func VerifyCrc(section []byte) error {
+	if len(section) < COMMON_FILED_LENGTH+CRC_LENGTH {
+		return fmt.Errorf("Section is too short to have CRC_32. (%d bytes)", len(section))
+	}
+	tail := len(section) - CRC_LENGTH
+	calculated := Crc32(section[:tail])
+	expected := binary.BigEndian.Uint32(section[tail:])
+	if calculated != expected {
+		return &CrcError{
+			TableId:    section[0],
+			Expected:   expected,
+			Calculated: calculated,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package psi
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestCrc32(t *testing.T) {
+	cases := []struct {
+		name   string
+		buffer []byte
+		crc    uint32
+	}{
+		{"check value", []byte("123456789"), 0x0376E6E7},
+		{"empty", []byte{}, 0xFFFFFFFF},
+		{"zero", []byte{0x00}, 0x4E08BFB4},
+	}
+	for _, c := range cases {
+		if crc := Crc32(c.buffer); c.crc != crc {
+			t.Errorf("%s: Crc32 returns 0x%08X, want 0x%08X", c.name, crc, c.crc)
+		}
+	}
+}
+
+func TestVerifyCrc(t *testing.T) {
+	// PAT of program_number 1 and PMT PID 0x0100
+	pat := []byte{0x00, 0xB0, 0x0D, 0x00, 0x01, 0xC1, 0x00, 0x00, 0x00, 0x01, 0xE1, 0x00, 0x00, 0x00, 0x00, 0x00}
+	binary.BigEndian.PutUint32(pat[12:], Crc32(pat[:12]))
+
+	broken := append([]byte{}, pat...)
+	broken[len(broken)-1] ^= 0x01
+
+	cases := []struct {
+		name    string
+		section []byte
+		crcErr  bool
+		err     bool
+	}{
+		{"valid", pat, false, false},
+		{"broken", broken, true, true},
+		{"short", []byte{0x00, 0xB0, 0x00, 0x00}, false, true},
+	}
+	for _, c := range cases {
+		err := VerifyCrc(c.section)
+		if c.err != (nil != err) {
+			t.Errorf("%s: VerifyCrc returns %v", c.name, err)
+			continue
+		}
+		if _, ok := err.(*CrcError); c.crcErr != ok {
+			t.Errorf("%s: VerifyCrc returns %T, CrcError is expected %v", c.name, err, c.crcErr)
+		}
+	}
+}
@@ -1,7 +1,12 @@
 package psi
 
 import (
-//"mpeg2ts/character"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"mpeg2ts/character"
 )
 
 type (
@@ -10,19 +15,50 @@ type (
 		Length byte
 	}
 
+	// Descriptor which is not parsed. (unknown tag)
+	RawDescriptor struct {
+		DescriptorCommon
+		Data []byte
+	}
+
+	// DescriptorError is returned when body of known descriptor is broken.
+	// The descriptor is returned as RawDescriptor with this error.
+	DescriptorError struct {
+		Tag byte
+		Err error
+	}
+
+	// Parse data which follows descriptor_length.
+	descriptorFunc func(common DescriptorCommon, data []byte) (interface{}, error)
+
+	// Descriptor Tag Number : 0x09
+	// CAPid is EMM PID in CAT, and ECM PID in PMT.
+	CADescriptor struct {
+		DescriptorCommon
+		CASystemId  uint16
+		reserved    byte
+		CAPid       uint16
+		PrivateData []byte
+	}
+
 	// Descriptor Tag Number : 0x4D
+	// Article has raw event_name and text, and EventName and Text are decoded ones.
 	EventDescriptor struct {
 		DescriptorCommon
-		LanguageCode []byte
+		LanguageCode string
 		Article
+		EventName string
+		Text      string
 	}
 
 	// Descriptor Tag Number : 0x4E
+	// Articles are items (item_description and item), and ExtendDescriptor is text.
+	// NOTE item can be split across descriptors. Use ExtendEventItems to get decoded items.
 	ExtendEventDescriptor struct {
 		DescriptorCommon
 		Number        byte
 		LastNumber    byte
-		LanguageCode  []byte
+		LanguageCode  string
 		ArticleLength byte
 
 		Articles []Article
@@ -38,58 +74,286 @@ type (
 		NameDescriptorLength byte
 		NameDescriptor       []byte
 	}
+
+	// Decoded item of extended event descriptors.
+	ExtendEventItem struct {
+		Name string
+		Text string
+	}
 )
 
 const (
+	CATag          = 0x09
 	EventTag       = 0x4D
 	ExtendEventTag = 0x4E
 
 	LANGUAGE_JPN = "jpn"
 )
 
-func ParseDescriptor(buffer []byte) (interface{}, uint) {
-	switch buffer[0] {
-	case EventTag:
-		return ParseEventDescriptor(buffer)
-	case ExtendEventTag:
-		return ParseExtendEventDescriptor(buffer)
+// Functions of typed descriptors by tag.
+var descriptorFunctions = map[byte]descriptorFunc{}
+
+func init() {
+	descriptorFunctions = map[byte]descriptorFunc{
+		CATag: func(common DescriptorCommon, data []byte) (interface{}, error) {
+			return parseCADescriptorBody(common, data)
+		},
+		EventTag: func(common DescriptorCommon, data []byte) (interface{}, error) {
+			return parseEventDescriptorBody(common, data)
+		},
+		ExtendEventTag: func(common DescriptorCommon, data []byte) (interface{}, error) {
+			return parseExtendEventDescriptorBody(common, data)
+		},
+		NetworkNameTag:               parseNetworkNameDescriptor,
+		BouquetNameTag:               parseBouquetNameDescriptor,
+		ServiceTag:                   parseServiceDescriptor,
+		ServiceListTag:               parseServiceListDescriptor,
+		SatelliteDeliverySystemTag:   parseSatelliteDeliverySystemDescriptor,
+		CableDeliverySystemTag:       parseCableDeliverySystemDescriptor,
+		TSInformationTag:             parseTSInformationDescriptor,
+		TerrestrialDeliverySystemTag: parseTerrestrialDeliverySystemDescriptor,
+		PartialReceptionTag:          parsePartialReceptionDescriptor,
+		ContentTag:                   parseContentDescriptor,
+		ComponentTag:                 parseComponentDescriptor,
+		AudioComponentTag:            parseAudioComponentDescriptor,
+		ParentalRatingTag:            parseParentalRatingDescriptor,
+		EventGroupTag:                parseEventGroupDescriptor,
+	}
+}
+
+// ParseDescriptor parses one descriptor at head of buffer, and returns it with its size.
+// Descriptor which has unknown tag is returned as RawDescriptor.
+// Descriptor whose body is broken is returned as RawDescriptor with *DescriptorError.
+// Time fields are decoded in JST.
+func ParseDescriptor(buffer []byte) (interface{}, uint, error) {
+	return parseDescriptor(buffer, JST)
+}
+
+func parseDescriptor(buffer []byte, loc *time.Location) (interface{}, uint, error) {
+	if len(buffer) < 2 {
+		return nil, 0, fmt.Errorf("Invalid buffer size '%d' for descriptor.", len(buffer))
+	}
+	common := ParseDescriptorCommon(buffer)
+	size := uint(common.Length) + 2
+	if uint(len(buffer)) < size {
+		return nil, 0, fmt.Errorf("Invalid descriptor length %d for tag 0x%02X.", common.Length, common.Tag)
+	}
+	buffer = buffer[:size]
+
+	raw := RawDescriptor{
+		DescriptorCommon: common,
+		Data:             buffer[2:],
+	}
+	var descriptor interface{}
+	var err error
+	switch common.Tag {
+	case LocalTimeOffsetTag:
+		descriptor, err = parseLocalTimeOffsetDescriptor(common, buffer[2:], loc)
+	case SeriesTag:
+		descriptor, err = parseSeriesDescriptor(common, buffer[2:], loc)
 	default:
-		common := ParseDescriptorCommon(buffer)
-		return nil, uint(common.Length + 2)
+		f, ok := descriptorFunctions[common.Tag]
+		if !ok {
+			return raw, size, nil
+		}
+		descriptor, err = f(common, buffer[2:])
+	}
+	if nil != err {
+		return raw, size, &DescriptorError{Tag: common.Tag, Err: err}
+	}
+	return descriptor, size, nil
+}
+
+// ParseDescriptors parses all descriptors in buffer. (e.g. descriptor loop)
+// Descriptors whose body is broken are returned as RawDescriptor, and the first *DescriptorError is returned.
+// If descriptor loop itself is broken, descriptors before it are returned with the error.
+func ParseDescriptors(buffer []byte) (descriptors []interface{}, err error) {
+	return parseDescriptors(buffer, JST)
+}
+
+func parseDescriptors(buffer []byte, loc *time.Location) (descriptors []interface{}, err error) {
+	for idx := uint(0); idx < uint(len(buffer)); {
+		descriptor, size, descriptorErr := parseDescriptor(buffer[idx:], loc)
+		if nil != descriptorErr {
+			if _, ok := descriptorErr.(*DescriptorError); !ok {
+				return descriptors, descriptorErr
+			}
+			if nil == err {
+				err = descriptorErr
+			}
+		}
+		descriptors = append(descriptors, descriptor)
+		idx += size
+	}
+	return descriptors, err
+}
+
+func (e *DescriptorError) Error() string {
+	return fmt.Sprintf("Broken descriptor of tag 0x%02X: %s", e.Tag, e.Err.Error())
+}
+
+func ParseCADescriptor(buffer []byte) (CADescriptor, uint, error) {
+	if len(buffer) < 2 {
+		return CADescriptor{}, 0, fmt.Errorf("Invalid buffer size '%d' for descriptor.", len(buffer))
+	}
+	common := ParseDescriptorCommon(buffer)
+	size := uint(common.Length) + 2
+	if uint(len(buffer)) < size {
+		return CADescriptor{}, 0, fmt.Errorf("Invalid descriptor length %d for tag 0x%02X.", common.Length, common.Tag)
+	}
+	cad, err := parseCADescriptorBody(common, buffer[2:size])
+	return cad, size, err
+}
+
+// Parse CA descriptor from data which follows descriptor_length.
+func parseCADescriptorBody(common DescriptorCommon, data []byte) (CADescriptor, error) {
+	if len(data) < 4 {
+		return CADescriptor{}, fmt.Errorf("CA descriptor is too short. (%d bytes)", len(data))
+	}
+	cad := CADescriptor{
+		DescriptorCommon: common,
+		CASystemId:       binary.BigEndian.Uint16(data[0:2]),
+		reserved:         data[2] & 0xE0 >> 5,
+		CAPid:            binary.BigEndian.Uint16([]byte{data[2] & 0x1F, data[3]}),
+		PrivateData:      data[4:],
+	}
+	return cad, nil
+}
+
+func ParseEventDescriptor(buffer []byte) (EventDescriptor, uint, error) {
+	if len(buffer) < 2 {
+		return EventDescriptor{}, 0, fmt.Errorf("Invalid buffer size '%d' for descriptor.", len(buffer))
+	}
+	common := ParseDescriptorCommon(buffer)
+	size := uint(common.Length) + 2
+	if uint(len(buffer)) < size {
+		return EventDescriptor{}, 0, fmt.Errorf("Invalid descriptor length %d for tag 0x%02X.", common.Length, common.Tag)
 	}
+	ed, err := parseEventDescriptorBody(common, buffer[2:size])
+	return ed, size, err
 }
 
-func ParseEventDescriptor(buffer []byte) (EventDescriptor, uint) {
+// Parse short event descriptor from data which follows descriptor_length.
+func parseEventDescriptorBody(common DescriptorCommon, data []byte) (EventDescriptor, error) {
+	if len(data) < 3 {
+		return EventDescriptor{}, fmt.Errorf("Event descriptor is too short. (%d bytes)", len(data))
+	}
 	ed := EventDescriptor{
-		DescriptorCommon: ParseDescriptorCommon(buffer),
-		LanguageCode:     buffer[2:5],
+		DescriptorCommon: common,
+		LanguageCode:     string(data[0:3]),
+	}
+	article, _, err := ParseArticle(data[3:])
+	if nil != err {
+		return EventDescriptor{}, err
+	}
+	ed.Article = article
+	ed.EventName = decodeString(article.Name)
+	ed.Text = decodeString(article.NameDescriptor)
+	return ed, nil
+}
+
+func ParseExtendEventDescriptor(buffer []byte) (ExtendEventDescriptor, uint, error) {
+	if len(buffer) < 2 {
+		return ExtendEventDescriptor{}, 0, fmt.Errorf("Invalid buffer size '%d' for descriptor.", len(buffer))
+	}
+	common := ParseDescriptorCommon(buffer)
+	size := uint(common.Length) + 2
+	if uint(len(buffer)) < size {
+		return ExtendEventDescriptor{}, 0, fmt.Errorf("Invalid descriptor length %d for tag 0x%02X.", common.Length, common.Tag)
 	}
-	size := uint(ed.Length + 2)
-	ed.Article, _ = ParseArticle(buffer[5:size])
-	return ed, size
+	eed, err := parseExtendEventDescriptorBody(common, buffer[2:size])
+	return eed, size, err
 }
 
-func ParseExtendEventDescriptor(buffer []byte) (ExtendEventDescriptor, uint) {
+// Parse extended event descriptor from data which follows descriptor_length.
+func parseExtendEventDescriptorBody(common DescriptorCommon, data []byte) (ExtendEventDescriptor, error) {
+	if len(data) < 5 {
+		return ExtendEventDescriptor{}, fmt.Errorf("Extended event descriptor is too short. (%d bytes)", len(data))
+	}
 	eed := ExtendEventDescriptor{
-		DescriptorCommon: ParseDescriptorCommon(buffer),
-		Number:           buffer[2] & 0xF0 >> 4,
-		LastNumber:       buffer[2] & 0x0F,
-		LanguageCode:     buffer[3:6],
-		ArticleLength:    buffer[7],
+		DescriptorCommon: common,
+		Number:           data[0] & 0xF0 >> 4,
+		LastNumber:       data[0] & 0x0F,
+		LanguageCode:     string(data[1:4]),
+		ArticleLength:    data[4],
+	}
+	itemsTail := 5 + uint(eed.ArticleLength)
+	if uint(len(data)) < itemsTail+1 {
+		return ExtendEventDescriptor{}, fmt.Errorf("Invalid length_of_items %d.", eed.ArticleLength)
+	}
+	for idx := uint(5); idx < itemsTail; {
+		article, size, err := ParseArticle(data[idx:itemsTail])
+		if nil != err {
+			return ExtendEventDescriptor{}, err
+		}
+		eed.Articles = append(eed.Articles, article)
+		idx += size
+	}
+
+	eed.ExtendLength = data[itemsTail]
+	textTail := itemsTail + 1 + uint(eed.ExtendLength)
+	if uint(len(data)) < textTail {
+		return ExtendEventDescriptor{}, fmt.Errorf("Invalid text_length %d.", eed.ExtendLength)
+	}
+	eed.ExtendDescriptor = data[itemsTail+1 : textTail]
+	return eed, nil
+}
+
+// ExtendEventItems reassembles items of extended event descriptors, and returns decoded items in order.
+// Descriptors are ordered by descriptor_number.
+// Item whose item_description is empty continues previous item, and split item is concatenated before decoding.
+// REF ARIB TR-B14 第四編 第2部 5.1.2
+func ExtendEventItems(descriptors []ExtendEventDescriptor) []ExtendEventItem {
+	sorted := make([]ExtendEventDescriptor, len(descriptors))
+	copy(sorted, descriptors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Number < sorted[j].Number
+	})
+
+	names := [][]byte{}
+	texts := [][]byte{}
+	for _, eed := range sorted {
+		for _, article := range eed.Articles {
+			if 0 == len(article.Name) && 0 < len(texts) {
+				last := len(texts) - 1
+				texts[last] = append(texts[last], article.NameDescriptor...)
+				continue
+			}
+			names = append(names, article.Name)
+			texts = append(texts, append([]byte{}, article.NameDescriptor...))
+		}
+	}
+
+	items := make([]ExtendEventItem, len(names))
+	for idx := range names {
+		items[idx] = ExtendEventItem{
+			Name: decodeString(names[idx]),
+			Text: decodeString(texts[idx]),
+		}
 	}
-	size := uint(eed.Length + 2)
-	return eed, size
+	return items
 }
 
-func ParseArticle(buffer []byte) (Article, uint) {
+// ParseArticle parses pair of length-prefixed name and text, and returns it with its size.
+func ParseArticle(buffer []byte) (Article, uint, error) {
+	if len(buffer) < 1 {
+		return Article{}, 0, fmt.Errorf("Invalid buffer size '%d' for article.", len(buffer))
+	}
 	article := Article{
 		NameLength: buffer[0],
 	}
-	article.Name = buffer[1 : article.NameLength+1]
-	article.NameDescriptorLength = buffer[article.NameLength+1]
-	tail := article.NameDescriptorLength + article.NameLength + 2
-	article.NameDescriptor = buffer[article.NameLength+2 : tail]
-	return article, uint(tail)
+	nameTail := uint(article.NameLength) + 1
+	if uint(len(buffer)) < nameTail+1 {
+		return Article{}, 0, fmt.Errorf("Invalid name length %d for article.", article.NameLength)
+	}
+	article.Name = buffer[1:nameTail]
+	article.NameDescriptorLength = buffer[nameTail]
+	tail := nameTail + 1 + uint(article.NameDescriptorLength)
+	if uint(len(buffer)) < tail {
+		return Article{}, 0, fmt.Errorf("Invalid text length %d for article.", article.NameDescriptorLength)
+	}
+	article.NameDescriptor = buffer[nameTail+1 : tail]
+	return article, tail, nil
 }
 
 func ParseDescriptorCommon(buffer []byte) DescriptorCommon {
@@ -99,3 +363,8 @@ func ParseDescriptorCommon(buffer []byte) DescriptorCommon {
 	}
 	return common
 }
+
+// Decode ARIB 8bit-character string.
+func decodeString(buffer []byte) string {
+	return character.NewEBitCharacterDecorder().Decode(buffer)
+}
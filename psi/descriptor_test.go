@@ -0,0 +1,79 @@
+package psi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Returns extended event descriptor of descriptor_number which has items. (item_description and item in turn)
+func newTestExtendEventDescriptor(number byte, lastNumber byte, items ...[]byte) []byte {
+	itemBuffer := []byte{}
+	for idx := 0; idx+1 < len(items); idx += 2 {
+		itemBuffer = append(itemBuffer, byte(len(items[idx])))
+		itemBuffer = append(itemBuffer, items[idx]...)
+		itemBuffer = append(itemBuffer, byte(len(items[idx+1])))
+		itemBuffer = append(itemBuffer, items[idx+1]...)
+	}
+	data := append([]byte{number<<4 | lastNumber, 'j', 'p', 'n', byte(len(itemBuffer))}, itemBuffer...)
+	data = append(data, 0x00)
+	return append([]byte{ExtendEventTag, byte(len(data))}, data...)
+}
+
+func TestExtendEventItems(t *testing.T) {
+	// 出演 and 番組内容 in JIS X 0208
+	cast := []byte{0x3D, 0x50, 0x31, 0x69}
+	content := []byte{0x48, 0x56, 0x41, 0x48, 0x46, 0x62, 0x4D, 0x46}
+	// 山田太郎 in JIS X 0208
+	name := []byte{0x3B, 0x33, 0x45, 0x44, 0x42, 0x40, 0x4F, 0x3A}
+
+	cases := []struct {
+		name        string
+		descriptors [][]byte
+		items       []ExtendEventItem
+	}{
+		{
+			name: "items",
+			descriptors: [][]byte{
+				newTestExtendEventDescriptor(0, 0, cast, name, content, name[:4]),
+			},
+			items: []ExtendEventItem{{"出演", "山田太郎"}, {"番組内容", "山田"}},
+		},
+		{
+			name: "item split at character boundary",
+			descriptors: [][]byte{
+				newTestExtendEventDescriptor(0, 1, cast, name[:4]),
+				newTestExtendEventDescriptor(1, 1, []byte{}, name[4:]),
+			},
+			items: []ExtendEventItem{{"出演", "山田太郎"}},
+		},
+		{
+			name: "item split inside multi-byte character",
+			descriptors: [][]byte{
+				newTestExtendEventDescriptor(0, 1, cast, name[:5]),
+				newTestExtendEventDescriptor(1, 1, []byte{}, name[5:], content, name[:2]),
+			},
+			items: []ExtendEventItem{{"出演", "山田太郎"}, {"番組内容", "山"}},
+		},
+		{
+			name: "descriptors in reverse order",
+			descriptors: [][]byte{
+				newTestExtendEventDescriptor(1, 1, []byte{}, name[3:]),
+				newTestExtendEventDescriptor(0, 1, cast, name[:3]),
+			},
+			items: []ExtendEventItem{{"出演", "山田太郎"}},
+		},
+	}
+	for _, c := range cases {
+		descriptors := []ExtendEventDescriptor{}
+		for _, buffer := range c.descriptors {
+			eed, _, err := ParseExtendEventDescriptor(buffer)
+			if nil != err {
+				t.Fatalf("%s: ParseExtendEventDescriptor returns %v", c.name, err)
+			}
+			descriptors = append(descriptors, eed)
+		}
+		if items := ExtendEventItems(descriptors); !reflect.DeepEqual(c.items, items) {
+			t.Errorf("%s: ExtendEventItems returns %q, want %q", c.name, items, c.items)
+		}
+	}
+}
@@ -26,42 +26,58 @@ type (
 		Crc []byte
 	}
 
+	// Deprecated: descriptors of event are parsed into EITEvent.Descriptors.
+	EITDescriptor struct {
+		Tag    byte
+		Length byte
+		Data   []byte
+	}
+
 	EITEvent struct {
 		EventId               uint16
 		StartTime             time.Time
 		Duration              time.Duration
+		DurationUndefined     bool // duration is not defined. (e.g. live program which may be extended)
 		RunningStatus         byte
 		FreeCAMode            bool
 		DescriptorsLoopLength uint16
 
+		// Typed descriptors (e.g. EventDescriptor) or RawDescriptor in order.
 		Descriptors []interface{}
 	}
-
-	EITDescriptor struct {
-		Tag    byte
-		Length byte
-		Data   []byte
-	}
-)
-
-const (
-	TIME_LAYOUT = "2006/01/02 15:04:05 MST"
 )
 
 var EIT_FIELD_LENGTH = 11
 var EIT_EVENT_FIELD_LENGTH = 12
 
+// ParseEit parses EIT with decoding start_time in JST.
+// If some descriptors are broken, they are kept as RawDescriptor and EIT is returned with the error.
 func ParseEit(buffer []byte) (interface{}, error) {
-	pointerField := buffer[0]
-	commonTail := (pointerField + 1) + COMMON_FILED_LENGTH // 1 is pointerField size
+	return parseEit(buffer, JST)
+}
+
+// EitParser returns table function of EIT which decodes start_time in loc.
+func EitParser(loc *time.Location) TableFunc {
+	return func(buffer []byte) (interface{}, error) {
+		return parseEit(buffer, loc)
+	}
+}
+
+func parseEit(buffer []byte, loc *time.Location) (interface{}, error) {
+	if 0 < len(buffer) && !IsEitTableId(buffer[0]) {
+		// Not EIT. (e.g. stuffing table)
+		return nil, nil
+	}
 
 	eit := &EITField{}
-	err := ParseCommon(buffer[(pointerField+1):commonTail], &eit.Common)
+	eitBuffer, err := parseSectionHeader(buffer, &eit.Common)
 	if nil != err {
 		return nil, err
 	}
+	if len(eitBuffer) < EIT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("EIT section is too short. (section_length is %d)", eit.SectionLength)
+	}
 
-	eitBuffer := buffer[commonTail:]
 	eit.ServiceId = uint(binary.BigEndian.Uint16(eitBuffer[0:2]))
 	eit.reserved2 = eitBuffer[2] & 0xC0 >> 6
 	eit.Version = eitBuffer[2] & 0x3E >> 1
@@ -73,12 +89,26 @@ func ParseEit(buffer []byte) (interface{}, error) {
 	eit.SegmentLastSectionNumber = eitBuffer[9]
 	eit.LastTableId = eitBuffer[10]
 
+	// Error of first broken descriptor, which is returned with eit.
+	var brokenErr error
 	eventBuffer := eitBuffer[EIT_FIELD_LENGTH : eit.SectionLength-4]
 	for idx := uint(0); uint(len(eventBuffer)) > idx; {
+		if uint(len(eventBuffer)) < idx+uint(EIT_EVENT_FIELD_LENGTH) {
+			return nil, fmt.Errorf("EIT event loop is broken. (%d bytes remain)", uint(len(eventBuffer))-idx)
+		}
+		startTime, err := decodeTime(eventBuffer[idx+2:idx+7], loc)
+		if nil != err {
+			return nil, err
+		}
+		duration, durationUndefined, err := decodeDuration(eventBuffer[idx+7 : idx+10])
+		if nil != err {
+			return nil, err
+		}
 		event := EITEvent{
 			EventId:               binary.BigEndian.Uint16(eventBuffer[idx : idx+2]),
-			StartTime:             decodeTime(eventBuffer[idx+2 : idx+7]),
-			Duration:              decodeDuration(eventBuffer[idx+7 : idx+10]),
+			StartTime:             startTime,
+			Duration:              duration,
+			DurationUndefined:     durationUndefined,
 			RunningStatus:         eventBuffer[idx+10] & 0xE0 >> 5,
 			FreeCAMode:            eventBuffer[idx+10]&0x10 > 0,
 			DescriptorsLoopLength: binary.BigEndian.Uint16([]byte{eventBuffer[idx+10] & 0x0F, eventBuffer[idx+11]}),
@@ -86,77 +116,192 @@ func ParseEit(buffer []byte) (interface{}, error) {
 
 		descriptorHead := idx + uint(EIT_EVENT_FIELD_LENGTH)
 		descriptorTail := descriptorHead + uint(event.DescriptorsLoopLength)
-		descriptorBuffer := eventBuffer[descriptorHead:descriptorTail]
-		for readSize := uint(0); uint(len(descriptorBuffer)) > readSize; {
-			// TODO store each Descriptors
-			_, descriptorSize := ParseDescriptor(descriptorBuffer[readSize:])
-			readSize += descriptorSize
+		if uint(len(eventBuffer)) < descriptorTail {
+			return nil, fmt.Errorf("Invalid descriptors_loop_length %d for event 0x%04X.", event.DescriptorsLoopLength, event.EventId)
 		}
+		// NOTE event is kept even if some descriptors are broken.
+		descriptors, descriptorErr := parseDescriptors(eventBuffer[descriptorHead:descriptorTail], loc)
+		event.Descriptors = descriptors
+		if nil != descriptorErr && nil == brokenErr {
+			brokenErr = fmt.Errorf("Event 0x%04X has broken descriptor. (%s)", event.EventId, descriptorErr.Error())
+		}
+		eit.Events = append(eit.Events, event)
 		idx = descriptorTail
 	}
 
 	eit.Crc = eitBuffer[eit.SectionLength-4 : eit.SectionLength]
-	// TODO check CRC
 
-	return eit, nil
+	return eit, brokenErr
+}
+
+// EndTime returns start_time + duration. It returns false if start_time or duration is undefined.
+func (event *EITEvent) EndTime() (time.Time, bool) {
+	if event.StartTime.IsZero() || event.DurationUndefined {
+		return time.Time{}, false
+	}
+	return event.StartTime.Add(event.Duration), true
+}
+
+// EventDescriptor returns short event descriptor of event.
+func (event *EITEvent) EventDescriptor() (EventDescriptor, bool) {
+	for _, descriptor := range event.Descriptors {
+		if ed, ok := descriptor.(EventDescriptor); ok {
+			return ed, true
+		}
+	}
+	return EventDescriptor{}, false
 }
 
-func decodeTime(buffer []byte) time.Time {
-	if 5 != len(buffer) {
-		panic("")
+// ExtendEventDescriptors returns extended event descriptors of event in order.
+func (event *EITEvent) ExtendEventDescriptors() []ExtendEventDescriptor {
+	descriptors := []ExtendEventDescriptor{}
+	for _, descriptor := range event.Descriptors {
+		if eed, ok := descriptor.(ExtendEventDescriptor); ok {
+			descriptors = append(descriptors, eed)
+		}
 	}
+	return descriptors
+}
+
+// ExtendEventItems returns decoded items of extended event descriptors. (e.g. cast, staff)
+func (event *EITEvent) ExtendEventItems() []ExtendEventItem {
+	return ExtendEventItems(event.ExtendEventDescriptors())
+}
 
-	// MJD to JTC
-	mjd := binary.BigEndian.Uint32(append([]byte{0x0, 0x0}, buffer[:2]...))
-	mjdF := float64(mjd)
-	tmpY := math.Trunc((mjdF - 15078.2) / 365.25)
-	tmp := tmpY * 365.25
-	tmpM := math.Trunc(mjdF-14956.1-math.Trunc(tmp)) / 30.6001
-	day := mjd - 14956 - uint32(tmp) - uint32(tmpM*30.6001)
-	k := uint32(0)
-	if tmpM > 13 {
+// Contents returns genres of content descriptor.
+func (event *EITEvent) Contents() []Content {
+	for _, descriptor := range event.Descriptors {
+		if cd, ok := descriptor.(ContentDescriptor); ok {
+			return cd.Contents
+		}
+	}
+	return nil
+}
+
+// Components returns component descriptors of event. (e.g. video)
+func (event *EITEvent) Components() []ComponentDescriptor {
+	components := []ComponentDescriptor{}
+	for _, descriptor := range event.Descriptors {
+		if cd, ok := descriptor.(ComponentDescriptor); ok {
+			components = append(components, cd)
+		}
+	}
+	return components
+}
+
+// AudioComponents returns audio component descriptors of event.
+func (event *EITEvent) AudioComponents() []AudioComponentDescriptor {
+	components := []AudioComponentDescriptor{}
+	for _, descriptor := range event.Descriptors {
+		if acd, ok := descriptor.(AudioComponentDescriptor); ok {
+			components = append(components, acd)
+		}
+	}
+	return components
+}
+
+// SeriesDescriptor returns series descriptor of event.
+func (event *EITEvent) SeriesDescriptor() (SeriesDescriptor, bool) {
+	for _, descriptor := range event.Descriptors {
+		if sd, ok := descriptor.(SeriesDescriptor); ok {
+			return sd, true
+		}
+	}
+	return SeriesDescriptor{}, false
+}
+
+// EventGroups returns event group descriptors of event. (e.g. shared event, relay)
+func (event *EITEvent) EventGroups() []EventGroupDescriptor {
+	groups := []EventGroupDescriptor{}
+	for _, descriptor := range event.Descriptors {
+		if egd, ok := descriptor.(EventGroupDescriptor); ok {
+			groups = append(groups, egd)
+		}
+	}
+	return groups
+}
+
+// ParentalRatings returns ratings of parental rating descriptor.
+func (event *EITEvent) ParentalRatings() []ParentalRating {
+	for _, descriptor := range event.Descriptors {
+		if prd, ok := descriptor.(ParentalRatingDescriptor); ok {
+			return prd.Ratings
+		}
+	}
+	return nil
+}
+
+func IsEitTableId(tableId byte) bool {
+	return EIT_PF_ACTUAL_TABLE_ID <= tableId && EIT_SCHEDULE_LAST_TABLE_ID >= tableId
+}
+
+// Decode MJD and BCD time in loc.
+// NOTE it returns zero time when all bits are 1. (undefined time)
+// REF ETSI EN 300 468 Annex C
+func decodeTime(buffer []byte, loc *time.Location) (time.Time, error) {
+	if TIME_FIELD_LENGTH != len(buffer) {
+		return time.Time{}, fmt.Errorf("Invalid buffer size '%d' for time.", len(buffer))
+	}
+	undefined := true
+	for _, b := range buffer {
+		if 0xFF != b {
+			undefined = false
+		}
+	}
+	if undefined {
+		return time.Time{}, nil
+	}
+
+	// MJD to year, month and day
+	mjd := float64(binary.BigEndian.Uint16(buffer[0:2]))
+	tmpY := math.Trunc((mjd - 15078.2) / 365.25)
+	tmpM := math.Trunc((mjd - 14956.1 - math.Trunc(tmpY*365.25)) / 30.6001)
+	day := int(mjd - 14956 - math.Trunc(tmpY*365.25) - math.Trunc(tmpM*30.6001))
+	k := 0
+	if 14 == tmpM || 15 == tmpM {
 		k = 1
 	}
-	year := 1900 + uint32(tmpY) + k
-	month := uint32(tmpM) - 1 - k*12
-
-	// TODO enable to change location
-	hour, minute, second := decodeHMS(buffer[2:5])
-	timeStr := fmt.Sprintf("%d/%02d/%02d %02d:%02d:%02d JST",
-		year,
-		month,
-		day,
-		hour,
-		minute,
-		second)
-	decoded, err := time.Parse(TIME_LAYOUT, timeStr)
+	year := 1900 + int(tmpY) + k
+	month := int(tmpM) - 1 - k*12
+
+	hour, minute, second, err := decodeHMS(buffer[2:5])
 	if nil != err {
-		panic(err)
+		return time.Time{}, err
+	}
+	if 23 < hour || 59 < minute || 60 < second {
+		return time.Time{}, fmt.Errorf("Invalid time %02X%02X%02X.", buffer[2], buffer[3], buffer[4])
 	}
-	return decoded
+	if nil == loc {
+		loc = JST
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, loc), nil
 }
 
-func decodeDuration(buffer []byte) time.Duration {
-	hour, minute, second := decodeHMS(buffer)
-	duration, err := time.ParseDuration(fmt.Sprintf("%dh%dm%ds", hour, minute, second))
+// Decode BCD duration. undefined is true when all bits are 1.
+func decodeDuration(buffer []byte) (duration time.Duration, undefined bool, err error) {
+	if 3 == len(buffer) && 0xFF == buffer[0] && 0xFF == buffer[1] && 0xFF == buffer[2] {
+		return 0, true, nil
+	}
+	hour, minute, second, err := decodeHMS(buffer)
 	if nil != err {
-		panic(err)
+		return 0, false, err
 	}
-	return duration
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second, false, nil
 }
 
-func decodeHMS(buffer []byte) (hour int, minute int, second int) {
+// Decode 6 digits BCD of hour, minute and second.
+func decodeHMS(buffer []byte) (hour int, minute int, second int, err error) {
 	if 3 != len(buffer) {
-		panic("")
+		return 0, 0, 0, fmt.Errorf("Invalid buffer size '%d' for BCD time.", len(buffer))
 	}
-
-	// unknown duration fill in 1 all bits
-	if 0xFF == buffer[0] && 0xFF == buffer[1] && 0xFF == buffer[2] {
-		return 0, 0, 0
+	for _, b := range buffer {
+		if 9 < b>>4 || 9 < b&0x0F {
+			return 0, 0, 0, fmt.Errorf("Invalid BCD time %02X%02X%02X.", buffer[0], buffer[1], buffer[2])
+		}
 	}
 
 	hour = int(buffer[0]&0xF0>>4*10 + buffer[0]&0x0F)
 	minute = int(buffer[1]&0xF0>>4*10 + buffer[1]&0x0F)
 	second = int(buffer[2]&0xF0>>4*10 + buffer[2]&0x0F)
-	return hour, minute, second
+	return hour, minute, second, nil
 }
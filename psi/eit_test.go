@@ -0,0 +1,60 @@
+package psi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeTime(t *testing.T) {
+	cases := []struct {
+		name   string
+		buffer []byte
+		loc    *time.Location
+		time   time.Time
+		err    bool
+	}{
+		{"JST", []byte{0xC0, 0x79, 0x12, 0x45, 0x00}, JST, time.Date(1993, 10, 13, 12, 45, 0, 0, JST), false},
+		{"UTC", []byte{0xC0, 0x79, 0x12, 0x45, 0x00}, time.UTC, time.Date(1993, 10, 13, 12, 45, 0, 0, time.UTC), false},
+		{"default", []byte{0xC0, 0x79, 0x12, 0x45, 0x00}, nil, time.Date(1993, 10, 13, 12, 45, 0, 0, JST), false},
+		{"undefined", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, JST, time.Time{}, false},
+		{"invalid hour", []byte{0xC0, 0x79, 0x24, 0x00, 0x00}, JST, time.Time{}, true},
+		{"invalid BCD", []byte{0xC0, 0x79, 0x1A, 0x00, 0x00}, JST, time.Time{}, true},
+		{"short", []byte{0xC0, 0x79, 0x12, 0x45}, JST, time.Time{}, true},
+	}
+	for _, c := range cases {
+		decoded, err := decodeTime(c.buffer, c.loc)
+		if c.err != (nil != err) {
+			t.Errorf("%s: decodeTime returns %v", c.name, err)
+			continue
+		}
+		if !decoded.Equal(c.time) || decoded.Location().String() != c.time.Location().String() {
+			t.Errorf("%s: decodeTime returns %v, want %v", c.name, decoded, c.time)
+		}
+	}
+}
+
+func TestDecodeDuration(t *testing.T) {
+	cases := []struct {
+		name      string
+		buffer    []byte
+		duration  time.Duration
+		undefined bool
+		err       bool
+	}{
+		{"duration", []byte{0x01, 0x45, 0x30}, time.Hour + 45*time.Minute + 30*time.Second, false, false},
+		{"zero", []byte{0x00, 0x00, 0x00}, 0, false, false},
+		{"undefined", []byte{0xFF, 0xFF, 0xFF}, 0, true, false},
+		{"invalid BCD", []byte{0x00, 0x6A, 0x00}, 0, false, true},
+		{"short", []byte{0x01, 0x45}, 0, false, true},
+	}
+	for _, c := range cases {
+		duration, undefined, err := decodeDuration(c.buffer)
+		if c.err != (nil != err) {
+			t.Errorf("%s: decodeDuration returns %v", c.name, err)
+			continue
+		}
+		if c.duration != duration || c.undefined != undefined {
+			t.Errorf("%s: decodeDuration returns %v %v, want %v %v", c.name, duration, undefined, c.duration, c.undefined)
+		}
+	}
+}
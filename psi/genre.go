@@ -0,0 +1,342 @@
+package psi
+
+// Genre tables of content descriptor.
+// Names are pairs of Japanese and English.
+
+const (
+	// content_nibble_level_1 of ARIB extension genre which is described by user_nibble.
+	ARIB_GENRE_EXTENSION = 0x0E
+
+	// content_nibble_level_2 of ARIB extension genre
+	ARIB_PROGRAM_ATTACHMENT = 0x00
+	ARIB_CS_EXTENSION       = 0x01
+)
+
+// REF ARIB STD-B10 第2部 付録H
+var aribLargeGenres = map[byte][2]string{
+	0x0: {"ニュース／報道", "News/Reports"},
+	0x1: {"スポーツ", "Sports"},
+	0x2: {"情報／ワイドショー", "Information/Tabloid shows"},
+	0x3: {"ドラマ", "Dramas"},
+	0x4: {"音楽", "Music"},
+	0x5: {"バラエティ", "Variety"},
+	0x6: {"映画", "Movies"},
+	0x7: {"アニメ／特撮", "Animation/Special effects"},
+	0x8: {"ドキュメンタリー／教養", "Documentary/Culture"},
+	0x9: {"劇場／公演", "Theatre/Public performance"},
+	0xA: {"趣味／教育", "Hobby/Education"},
+	0xB: {"福祉", "Welfare"},
+	0xE: {"拡張", "Extension"},
+	0xF: {"その他", "Others"},
+}
+
+// Key is content_nibble_level_1 << 4 | content_nibble_level_2.
+var aribMiddleGenres = map[byte][2]string{
+	0x00: {"定時・総合", "Regular/General"},
+	0x01: {"天気", "Weather report"},
+	0x02: {"特集・ドキュメント", "Special/Documentary"},
+	0x03: {"政治・国会", "Politics/National assembly"},
+	0x04: {"経済・市況", "Economics/Market report"},
+	0x05: {"海外・国際", "Overseas/International report"},
+	0x06: {"解説", "Commentary"},
+	0x07: {"討論・会談", "Discussion/Conference"},
+	0x08: {"報道特番", "Special report"},
+	0x09: {"ローカル・地域", "Local/Regional"},
+	0x0A: {"交通", "Traffic report"},
+	0x0F: {"その他", "Others"},
+
+	0x10: {"スポーツニュース", "Sports news"},
+	0x11: {"野球", "Baseball"},
+	0x12: {"サッカー", "Soccer"},
+	0x13: {"ゴルフ", "Golf"},
+	0x14: {"その他の球技", "Other ball games"},
+	0x15: {"相撲・格闘技", "Sumo/Combative sports"},
+	0x16: {"オリンピック・国際大会", "Olympics/International games"},
+	0x17: {"マラソン・陸上・水泳", "Marathon/Athletic sports/Swimming"},
+	0x18: {"モータースポーツ", "Motor sports"},
+	0x19: {"マリン・ウィンタースポーツ", "Marine sports/Winter sports"},
+	0x1A: {"競馬・公営競技", "Horse race/Public race"},
+	0x1F: {"その他", "Others"},
+
+	0x20: {"芸能・ワイドショー", "Gossip/Tabloid shows"},
+	0x21: {"ファッション", "Fashion"},
+	0x22: {"暮らし・住まい", "Living/Home"},
+	0x23: {"健康・医療", "Health/Medical care"},
+	0x24: {"ショッピング・通販", "Shopping/Mail-order business"},
+	0x25: {"グルメ・料理", "Gourmet/Cooking"},
+	0x26: {"イベント", "Events"},
+	0x27: {"番組紹介・お知らせ", "Program guide/Information"},
+	0x2F: {"その他", "Others"},
+
+	0x30: {"国内ドラマ", "Japanese dramas"},
+	0x31: {"海外ドラマ", "Overseas dramas"},
+	0x32: {"時代劇", "Period dramas"},
+	0x3F: {"その他", "Others"},
+
+	0x40: {"国内ロック・ポップス", "Japanese rock/Pop music"},
+	0x41: {"海外ロック・ポップス", "Overseas rock/Pop music"},
+	0x42: {"クラシック・オペラ", "Classical music/Opera"},
+	0x43: {"ジャズ・フュージョン", "Jazz/Fusion"},
+	0x44: {"歌謡曲・演歌", "Popular songs/Enka"},
+	0x45: {"ライブ・コンサート", "Live/Concert"},
+	0x46: {"ランキング・リクエスト", "Ranking/Request music"},
+	0x47: {"カラオケ・のど自慢", "Karaoke/Amateur singing contests"},
+	0x48: {"民謡・邦楽", "Japanese ballad/Japanese traditional music"},
+	0x49: {"童謡・キッズ", "Children's songs"},
+	0x4A: {"民族音楽・ワールドミュージック", "Folk music/World music"},
+	0x4F: {"その他", "Others"},
+
+	0x50: {"クイズ", "Quiz"},
+	0x51: {"ゲーム", "Game"},
+	0x52: {"トークバラエティ", "Talk variety"},
+	0x53: {"お笑い・コメディ", "Comedy"},
+	0x54: {"音楽バラエティ", "Music variety"},
+	0x55: {"旅バラエティ", "Travel variety"},
+	0x56: {"料理バラエティ", "Cooking variety"},
+	0x5F: {"その他", "Others"},
+
+	0x60: {"洋画", "Overseas movies"},
+	0x61: {"邦画", "Japanese movies"},
+	0x62: {"アニメ", "Animation"},
+	0x6F: {"その他", "Others"},
+
+	0x70: {"国内アニメ", "Japanese animation"},
+	0x71: {"海外アニメ", "Overseas animation"},
+	0x72: {"特撮", "Special effects"},
+	0x7F: {"その他", "Others"},
+
+	0x80: {"社会・時事", "Society/Current events"},
+	0x81: {"歴史・紀行", "History/Travel record"},
+	0x82: {"自然・動物・環境", "Nature/Animals/Environment"},
+	0x83: {"宇宙・科学・医学", "Space/Science/Medical science"},
+	0x84: {"カルチャー・伝統文化", "Culture/Traditional culture"},
+	0x85: {"文学・文芸", "Literature/Literary art"},
+	0x86: {"スポーツ", "Sports"},
+	0x87: {"ドキュメンタリー全般", "Total documentary"},
+	0x88: {"インタビュー・討論", "Interview/Discussion"},
+	0x8F: {"その他", "Others"},
+
+	0x90: {"現代劇・新劇", "Modern drama/Western-style drama"},
+	0x91: {"ミュージカル", "Musical"},
+	0x92: {"ダンス・バレエ", "Dance/Ballet"},
+	0x93: {"落語・演芸", "Comic story/Entertainment"},
+	0x94: {"歌舞伎・古典", "Kabuki/Classical drama"},
+	0x9F: {"その他", "Others"},
+
+	0xA0: {"旅・釣り・アウトドア", "Travel/Fishing/Outdoor"},
+	0xA1: {"園芸・ペット・手芸", "Gardening/Pets/Handicrafts"},
+	0xA2: {"音楽・美術・工芸", "Music/Art/Industrial art"},
+	0xA3: {"囲碁・将棋", "Go/Shogi"},
+	0xA4: {"麻雀・パチンコ", "Mahjong/Pachinko"},
+	0xA5: {"車・オートバイ", "Cars/Motorbikes"},
+	0xA6: {"コンピュータ・ＴＶゲーム", "Computer/TV games"},
+	0xA7: {"会話・語学", "Conversation/Languages"},
+	0xA8: {"幼児・小学生", "Infants/Schoolchildren"},
+	0xA9: {"中学生・高校生", "Junior high school/High school students"},
+	0xAA: {"大学生・受験", "University students/Examinations"},
+	0xAB: {"生涯教育・資格", "Lifelong education/Qualifications"},
+	0xAC: {"教育問題", "Educational problems"},
+	0xAF: {"その他", "Others"},
+
+	0xB0: {"高齢者", "Elderly persons"},
+	0xB1: {"障害者", "Handicapped persons"},
+	0xB2: {"社会福祉", "Social welfare"},
+	0xB3: {"ボランティア", "Volunteers"},
+	0xB4: {"手話", "Sign language"},
+	0xB5: {"文字（字幕）", "Text (subtitles)"},
+	0xB6: {"音声解説", "Audio description"},
+	0xBF: {"その他", "Others"},
+
+	0xFF: {"その他", "Others"},
+}
+
+// Key is content_nibble_level_2 of extension genre.
+var aribExtensionGenres = map[byte][2]string{
+	ARIB_PROGRAM_ATTACHMENT: {"番組付属情報", "Program attachment information"},
+	ARIB_CS_EXTENSION:       {"広帯域CS拡張", "Wideband CS extension"},
+	0x03:                    {"サーバー型番組付属情報", "Server-type program attachment information"},
+	0x04:                    {"IP放送用番組付属情報", "Program attachment information for IP broadcasting"},
+}
+
+// Key is user_nibble_1 << 4 | user_nibble_2.
+var aribProgramAttachments = map[byte][2]string{
+	0x00: {"中止の可能性あり", "Possibly cancelled"},
+	0x01: {"延長の可能性あり", "Possibly extended"},
+	0x02: {"中断の可能性あり", "Possibly interrupted"},
+	0x03: {"同一シリーズの別話数放送の可能性あり", "Possibly another episode of the same series"},
+	0x04: {"編成未定枠", "Undetermined slot"},
+	0x05: {"繰り上げの可能性あり", "Possibly moved up"},
+	0x10: {"中断ニュースあり", "Interrupting news"},
+	0x11: {"当該イベントに関連する臨時サービスあり", "Temporary service related to this event"},
+	0x20: {"当該イベント中に3D映像あり", "3D video in this event"},
+}
+
+// Key is user_nibble_1.
+var aribCSLargeGenres = map[byte][2]string{
+	0x0: {"スポーツ(CS)", "Sports (CS)"},
+	0x1: {"洋画(CS)", "Overseas movies (CS)"},
+	0x2: {"邦画(CS)", "Japanese movies (CS)"},
+}
+
+// Key is user_nibble_1 << 4 | user_nibble_2.
+var aribCSMiddleGenres = map[byte][2]string{
+	0x00: {"テニス", "Tennis"},
+	0x01: {"バスケットボール", "Basketball"},
+	0x02: {"ラグビー", "Rugby"},
+	0x03: {"アメリカンフットボール", "American football"},
+	0x04: {"ボクシング", "Boxing"},
+	0x05: {"プロレス", "Professional wrestling"},
+	0x0F: {"その他", "Others"},
+
+	0x10: {"アクション", "Action"},
+	0x11: {"SF／ファンタジー", "Science fiction/Fantasy"},
+	0x12: {"コメディー", "Comedy"},
+	0x13: {"サスペンス／ミステリー", "Suspense/Mystery"},
+	0x14: {"恋愛／ロマンス", "Romance"},
+	0x15: {"ホラー／スリラー", "Horror/Thriller"},
+	0x16: {"ウエスタン", "Western"},
+	0x17: {"ドラマ／社会派ドラマ", "Drama/Social drama"},
+	0x18: {"アニメーション", "Animation"},
+	0x19: {"ドキュメンタリー", "Documentary"},
+	0x1A: {"アドベンチャー／冒険", "Adventure"},
+	0x1B: {"ミュージカル／音楽映画", "Musical/Music film"},
+	0x1C: {"ホームドラマ", "Family drama"},
+	0x1F: {"その他", "Others"},
+
+	0x20: {"アクション", "Action"},
+	0x21: {"SF／ファンタジー", "Science fiction/Fantasy"},
+	0x22: {"お笑い／コメディー", "Comedy"},
+	0x23: {"サスペンス／ミステリー", "Suspense/Mystery"},
+	0x24: {"恋愛／ロマンス", "Romance"},
+	0x25: {"ホラー／スリラー", "Horror/Thriller"},
+	0x26: {"青春／学園／アイドル", "Youth/School/Idol"},
+	0x27: {"任侠／時代劇", "Yakuza/Period drama"},
+	0x28: {"アニメーション", "Animation"},
+	0x29: {"ドキュメンタリー", "Documentary"},
+	0x2A: {"アドベンチャー／冒険", "Adventure"},
+	0x2B: {"ミュージカル／音楽映画", "Musical/Music film"},
+	0x2C: {"ホームドラマ", "Family drama"},
+	0x2F: {"その他", "Others"},
+}
+
+// REF ETSI EN 300 468 Table 29
+var dvbLargeGenres = map[byte]string{
+	0x1: "Movie/Drama",
+	0x2: "News/Current affairs",
+	0x3: "Show/Game show",
+	0x4: "Sports",
+	0x5: "Children's/Youth programmes",
+	0x6: "Music/Ballet/Dance",
+	0x7: "Arts/Culture (without music)",
+	0x8: "Social/Political issues/Economics",
+	0x9: "Education/Science/Factual topics",
+	0xA: "Leisure hobbies",
+	0xB: "Special characteristics",
+	0xF: "User defined",
+}
+
+// Key is content_nibble_level_1 << 4 | content_nibble_level_2.
+var dvbMiddleGenres = map[byte]string{
+	0x10: "movie/drama (general)",
+	0x11: "detective/thriller",
+	0x12: "adventure/western/war",
+	0x13: "science fiction/fantasy/horror",
+	0x14: "comedy",
+	0x15: "soap/melodrama/folklore",
+	0x16: "romance",
+	0x17: "serious/classical/religious/historical movie/drama",
+	0x18: "adult movie/drama",
+	0x1F: "user defined",
+
+	0x20: "news/current affairs (general)",
+	0x21: "news/weather report",
+	0x22: "news magazine",
+	0x23: "documentary",
+	0x24: "discussion/interview/debate",
+	0x2F: "user defined",
+
+	0x30: "show/game show (general)",
+	0x31: "game show/quiz/contest",
+	0x32: "variety show",
+	0x33: "talk show",
+	0x3F: "user defined",
+
+	0x40: "sports (general)",
+	0x41: "special events (Olympic Games, World Cup, etc.)",
+	0x42: "sports magazines",
+	0x43: "football/soccer",
+	0x44: "tennis/squash",
+	0x45: "team sports (excluding football)",
+	0x46: "athletics",
+	0x47: "motor sport",
+	0x48: "water sport",
+	0x49: "winter sports",
+	0x4A: "equestrian",
+	0x4B: "martial sports",
+	0x4F: "user defined",
+
+	0x50: "children's/youth programmes (general)",
+	0x51: "pre-school children's programmes",
+	0x52: "entertainment programmes for 6 to 14",
+	0x53: "entertainment programmes for 10 to 16",
+	0x54: "informational/educational/school programmes",
+	0x55: "cartoons/puppets",
+	0x5F: "user defined",
+
+	0x60: "music/ballet/dance (general)",
+	0x61: "rock/pop",
+	0x62: "serious music/classical music",
+	0x63: "folk/traditional music",
+	0x64: "jazz",
+	0x65: "musical/opera",
+	0x66: "ballet",
+	0x6F: "user defined",
+
+	0x70: "arts/culture (without music, general)",
+	0x71: "performing arts",
+	0x72: "fine arts",
+	0x73: "religion",
+	0x74: "popular culture/traditional arts",
+	0x75: "literature",
+	0x76: "film/cinema",
+	0x77: "experimental film/video",
+	0x78: "broadcasting/press",
+	0x79: "new media",
+	0x7A: "arts/culture magazines",
+	0x7B: "fashion",
+	0x7F: "user defined",
+
+	0x80: "social/political issues/economics (general)",
+	0x81: "magazines/reports/documentary",
+	0x82: "economics/social advisory",
+	0x83: "remarkable people",
+	0x8F: "user defined",
+
+	0x90: "education/science/factual topics (general)",
+	0x91: "nature/animals/environment",
+	0x92: "technology/natural sciences",
+	0x93: "medicine/physiology/psychology",
+	0x94: "foreign countries/expeditions",
+	0x95: "social/spiritual sciences",
+	0x96: "further education",
+	0x97: "languages",
+	0x9F: "user defined",
+
+	0xA0: "leisure hobbies (general)",
+	0xA1: "tourism/travel",
+	0xA2: "handicraft",
+	0xA3: "motoring",
+	0xA4: "fitness and health",
+	0xA5: "cooking",
+	0xA6: "advertisement/shopping",
+	0xA7: "gardening",
+	0xAF: "user defined",
+
+	0xB0: "original language",
+	0xB1: "black and white",
+	0xB2: "unpublished",
+	0xB3: "live broadcast",
+	0xB4: "plano-stereoscopic",
+	0xB5: "local or regional",
+	0xBF: "user defined",
+}
@@ -0,0 +1,236 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	// Descriptor Tag Number : 0x40
+	NetworkNameDescriptor struct {
+		DescriptorCommon
+		Name string
+	}
+
+	// Descriptor Tag Number : 0x41
+	ServiceListDescriptor struct {
+		DescriptorCommon
+		Services []ServiceListItem
+	}
+
+	ServiceListItem struct {
+		ServiceId   uint
+		ServiceType byte
+	}
+
+	// Descriptor Tag Number : 0x43
+	// NOTE Frequency, OrbitalPosition and SymbolRate are decoded from BCD.
+	SatelliteDeliverySystemDescriptor struct {
+		DescriptorCommon
+		Frequency       uint // 10kHz unit
+		OrbitalPosition uint // 0.1 degree unit
+		WestEastFlag    bool // true is east
+		Polarisation    byte
+		Modulation      byte
+		SymbolRate      uint // 100 symbol/s unit
+		FECInner        byte
+	}
+
+	// Descriptor Tag Number : 0x44
+	CableDeliverySystemDescriptor struct {
+		DescriptorCommon
+		Frequency  uint // 100Hz unit
+		reserved   uint
+		FECOuter   byte
+		Modulation byte
+		SymbolRate uint // 100 symbol/s unit
+		FECInner   byte
+	}
+
+	// Descriptor Tag Number : 0xCD
+	TSInformationDescriptor struct {
+		DescriptorCommon
+		RemoteControlKeyId    byte
+		TSNameLength          byte
+		TransmissionTypeCount byte
+		TSName                string
+
+		TransmissionTypes []TransmissionType
+	}
+
+	TransmissionType struct {
+		TransmissionTypeInfo byte
+		NumOfService         byte
+		ServiceIds           []uint
+	}
+
+	// Descriptor Tag Number : 0xFA (ISDB-T)
+	TerrestrialDeliverySystemDescriptor struct {
+		DescriptorCommon
+		AreaCode         uint
+		GuardInterval    byte
+		TransmissionMode byte
+		Frequencies      []uint // 1/7MHz unit
+	}
+
+	// Descriptor Tag Number : 0xFB
+	PartialReceptionDescriptor struct {
+		DescriptorCommon
+		ServiceIds []uint
+	}
+)
+
+const (
+	NetworkNameTag               = 0x40
+	ServiceListTag               = 0x41
+	SatelliteDeliverySystemTag   = 0x43
+	CableDeliverySystemTag       = 0x44
+	TSInformationTag             = 0xCD
+	TerrestrialDeliverySystemTag = 0xFA
+	PartialReceptionTag          = 0xFB
+)
+
+func parseNetworkNameDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	nnd := NetworkNameDescriptor{
+		DescriptorCommon: common,
+		Name:             decodeString(data),
+	}
+	return nnd, nil
+}
+
+func parseServiceListDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if 0 != len(data)%3 {
+		return nil, fmt.Errorf("Invalid service list descriptor length %d.", len(data))
+	}
+	sld := ServiceListDescriptor{
+		DescriptorCommon: common,
+	}
+	for idx := 0; idx < len(data); idx += 3 {
+		sld.Services = append(sld.Services, ServiceListItem{
+			ServiceId:   uint(binary.BigEndian.Uint16(data[idx : idx+2])),
+			ServiceType: data[idx+2],
+		})
+	}
+	return sld, nil
+}
+
+func parseSatelliteDeliverySystemDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < 11 {
+		return nil, fmt.Errorf("Satellite delivery system descriptor is too short. (%d bytes)", len(data))
+	}
+	sdsd := SatelliteDeliverySystemDescriptor{
+		DescriptorCommon: common,
+		Frequency:        decodeBCD(data[0:4], 8),
+		OrbitalPosition:  decodeBCD(data[4:6], 4),
+		WestEastFlag:     data[6]&0x80 > 0,
+		Polarisation:     data[6] & 0x60 >> 5,
+		Modulation:       data[6] & 0x1F,
+		SymbolRate:       decodeBCD(data[7:11], 7),
+		FECInner:         data[10] & 0x0F,
+	}
+	return sdsd, nil
+}
+
+func parseCableDeliverySystemDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < 11 {
+		return nil, fmt.Errorf("Cable delivery system descriptor is too short. (%d bytes)", len(data))
+	}
+	cdsd := CableDeliverySystemDescriptor{
+		DescriptorCommon: common,
+		Frequency:        decodeBCD(data[0:4], 8),
+		reserved:         uint(data[4])<<4 | uint(data[5]&0xF0>>4),
+		FECOuter:         data[5] & 0x0F,
+		Modulation:       data[6],
+		SymbolRate:       decodeBCD(data[7:11], 7),
+		FECInner:         data[10] & 0x0F,
+	}
+	return cdsd, nil
+}
+
+func parseTSInformationDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("TS information descriptor is too short. (%d bytes)", len(data))
+	}
+	tsid := TSInformationDescriptor{
+		DescriptorCommon:      common,
+		RemoteControlKeyId:    data[0],
+		TSNameLength:          data[1] & 0xFC >> 2,
+		TransmissionTypeCount: data[1] & 0x03,
+	}
+	idx := 2 + int(tsid.TSNameLength)
+	if len(data) < idx {
+		return nil, fmt.Errorf("Invalid length_of_ts_name %d.", tsid.TSNameLength)
+	}
+	tsid.TSName = decodeString(data[2:idx])
+
+	for count := byte(0); count < tsid.TransmissionTypeCount; count++ {
+		if len(data) < idx+2 {
+			return nil, fmt.Errorf("TS information descriptor is broken. (transmission type %d)", count)
+		}
+		transmissionType := TransmissionType{
+			TransmissionTypeInfo: data[idx],
+			NumOfService:         data[idx+1],
+		}
+		idx += 2
+		if len(data) < idx+2*int(transmissionType.NumOfService) {
+			return nil, fmt.Errorf("Invalid num_of_service %d.", transmissionType.NumOfService)
+		}
+		for service := byte(0); service < transmissionType.NumOfService; service++ {
+			transmissionType.ServiceIds = append(transmissionType.ServiceIds, uint(binary.BigEndian.Uint16(data[idx:idx+2])))
+			idx += 2
+		}
+		tsid.TransmissionTypes = append(tsid.TransmissionTypes, transmissionType)
+	}
+	return tsid, nil
+}
+
+func parseTerrestrialDeliverySystemDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < 2 || 0 != len(data)%2 {
+		return nil, fmt.Errorf("Invalid terrestrial delivery system descriptor length %d.", len(data))
+	}
+	tdsd := TerrestrialDeliverySystemDescriptor{
+		DescriptorCommon: common,
+		AreaCode:         uint(data[0])<<4 | uint(data[1]&0xF0>>4),
+		GuardInterval:    data[1] & 0x0C >> 2,
+		TransmissionMode: data[1] & 0x03,
+	}
+	for idx := 2; idx < len(data); idx += 2 {
+		tdsd.Frequencies = append(tdsd.Frequencies, uint(binary.BigEndian.Uint16(data[idx:idx+2])))
+	}
+	return tdsd, nil
+}
+
+func parsePartialReceptionDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if 0 != len(data)%2 {
+		return nil, fmt.Errorf("Invalid partial reception descriptor length %d.", len(data))
+	}
+	prd := PartialReceptionDescriptor{
+		DescriptorCommon: common,
+	}
+	for idx := 0; idx < len(data); idx += 2 {
+		prd.ServiceIds = append(prd.ServiceIds, uint(binary.BigEndian.Uint16(data[idx:idx+2])))
+	}
+	return prd, nil
+}
+
+// FrequenciesKHz returns center frequencies in kHz.
+func (tdsd TerrestrialDeliverySystemDescriptor) FrequenciesKHz() []uint {
+	frequencies := make([]uint, len(tdsd.Frequencies))
+	for idx, frequency := range tdsd.Frequencies {
+		frequencies[idx] = frequency * 1000 / 7
+	}
+	return frequencies
+}
+
+// Decode BCD which has digits from head of buffer.
+func decodeBCD(buffer []byte, digits int) uint {
+	value := uint(0)
+	for idx := 0; idx < digits; idx++ {
+		nibble := buffer[idx/2]
+		if 0 == idx%2 {
+			nibble >>= 4
+		}
+		value = value*10 + uint(nibble&0x0F)
+	}
+	return value
+}
@@ -0,0 +1,138 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	NITField struct {
+		Common
+		NetworkId                 uint
+		reserved2                 byte
+		VersionNumber             byte
+		CurrentNextIndicator      bool
+		SectionNumber             byte
+		LastSectionNumber         byte
+		reserved3                 byte
+		NetworkDescriptorsLength  uint16
+		Descriptors               []interface{}
+		reserved4                 byte
+		TransportStreamLoopLength uint16
+
+		TransportStreams []NITTransportStream
+
+		Crc []byte
+	}
+
+	// Element of transport stream loop. (BAT has same structure)
+	NITTransportStream struct {
+		TransportStreamId          uint
+		OriginalNetworkId          uint
+		reserved                   byte
+		TransportDescriptorsLength uint16
+
+		Descriptors []interface{}
+	}
+)
+
+const NIT_FIELD_LENGTH = 7
+const NIT_TRANSPORT_STREAM_FIELD_LENGTH = 6
+
+func ParseNit(buffer []byte) (interface{}, error) {
+	if 0 < len(buffer) && NIT_ACTUAL_TABLE_ID != buffer[0] && NIT_OTHER_TABLE_ID != buffer[0] {
+		// Not NIT. (e.g. stuffing table)
+		return nil, nil
+	}
+
+	nit := &NITField{}
+	nitBuffer, err := parseSectionHeader(buffer, &nit.Common)
+	if nil != err {
+		return nil, err
+	}
+	if len(nitBuffer) < NIT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("NIT section is too short. (section_length is %d)", nit.SectionLength)
+	}
+
+	nit.NetworkId = uint(binary.BigEndian.Uint16(nitBuffer[0:2]))
+	nit.reserved2 = nitBuffer[2] & 0xC0 >> 6
+	nit.VersionNumber = nitBuffer[2] & 0x3E >> 1
+	nit.CurrentNextIndicator = nitBuffer[2]&0x01 > 0
+	nit.SectionNumber = nitBuffer[3]
+	nit.LastSectionNumber = nitBuffer[4]
+	nit.reserved3 = nitBuffer[5] & 0xF0 >> 4
+	nit.NetworkDescriptorsLength = binary.BigEndian.Uint16([]byte{nitBuffer[5] & 0x0F, nitBuffer[6]})
+
+	crcHead := int(nit.SectionLength) - CRC_LENGTH
+	descriptorTail := NIT_FIELD_LENGTH + int(nit.NetworkDescriptorsLength)
+	if crcHead < descriptorTail+2 {
+		return nil, fmt.Errorf("Invalid network_descriptors_length %d. (section_length is %d)", nit.NetworkDescriptorsLength, nit.SectionLength)
+	}
+	// Error of first broken descriptor, which is returned with nit.
+	// NOTE network and transport streams are kept even if some descriptors are broken.
+	var brokenErr error
+	nit.Descriptors, err = ParseDescriptors(nitBuffer[NIT_FIELD_LENGTH:descriptorTail])
+	if nil != err {
+		brokenErr = fmt.Errorf("Network 0x%04X has broken descriptor. (%s)", nit.NetworkId, err.Error())
+	}
+
+	nit.reserved4 = nitBuffer[descriptorTail] & 0xF0 >> 4
+	nit.TransportStreamLoopLength = binary.BigEndian.Uint16([]byte{nitBuffer[descriptorTail] & 0x0F, nitBuffer[descriptorTail+1]})
+	loopHead := descriptorTail + 2
+	loopTail := loopHead + int(nit.TransportStreamLoopLength)
+	if crcHead < loopTail {
+		return nil, fmt.Errorf("Invalid transport_stream_loop_length %d. (section_length is %d)", nit.TransportStreamLoopLength, nit.SectionLength)
+	}
+
+	var streamErr error
+	nit.TransportStreams, streamErr, err = parseTransportStreamLoop(nitBuffer[loopHead:loopTail])
+	if nil != err {
+		return nil, err
+	}
+	if nil == brokenErr {
+		brokenErr = streamErr
+	}
+
+	nit.Crc = nitBuffer[crcHead:nit.SectionLength]
+	return nit, brokenErr
+}
+
+// Parse transport stream loop of NIT and BAT.
+// Transport streams which have broken descriptors are kept, and brokenErr is error of the first one.
+// err is returned when the loop itself is broken.
+func parseTransportStreamLoop(buffer []byte) (streams []NITTransportStream, brokenErr error, err error) {
+	for idx := 0; idx < len(buffer); {
+		if len(buffer) < idx+NIT_TRANSPORT_STREAM_FIELD_LENGTH {
+			return nil, nil, fmt.Errorf("Transport stream loop is broken. (%d bytes remain)", len(buffer)-idx)
+		}
+		ts := NITTransportStream{
+			TransportStreamId:          uint(binary.BigEndian.Uint16(buffer[idx : idx+2])),
+			OriginalNetworkId:          uint(binary.BigEndian.Uint16(buffer[idx+2 : idx+4])),
+			reserved:                   buffer[idx+4] & 0xF0 >> 4,
+			TransportDescriptorsLength: binary.BigEndian.Uint16([]byte{buffer[idx+4] & 0x0F, buffer[idx+5]}),
+		}
+		head := idx + NIT_TRANSPORT_STREAM_FIELD_LENGTH
+		tail := head + int(ts.TransportDescriptorsLength)
+		if len(buffer) < tail {
+			return nil, nil, fmt.Errorf("Invalid transport_descriptors_length %d for TS 0x%04X.", ts.TransportDescriptorsLength, ts.TransportStreamId)
+		}
+		descriptors, descriptorErr := ParseDescriptors(buffer[head:tail])
+		ts.Descriptors = descriptors
+		if nil != descriptorErr && nil == brokenErr {
+			brokenErr = fmt.Errorf("TS 0x%04X has broken descriptor. (%s)", ts.TransportStreamId, descriptorErr.Error())
+		}
+		streams = append(streams, ts)
+		idx = tail
+	}
+	return streams, brokenErr, nil
+}
+
+// NetworkName returns name in network name descriptor.
+func (nit *NITField) NetworkName() string {
+	for _, descriptor := range nit.Descriptors {
+		if nnd, ok := descriptor.(NetworkNameDescriptor); ok {
+			return nnd.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package psi
+
+import (
+	"testing"
+)
+
+// Returns NIT of network_id 1 which has network descriptors and transport stream loop.
+func newTestNit(networkDescriptors []byte, streamLoop []byte) []byte {
+	body := []byte{0x00, 0x01, 0xC1, 0x00, 0x00, 0xF0 | byte(len(networkDescriptors)>>8), byte(len(networkDescriptors))}
+	body = append(body, networkDescriptors...)
+	body = append(body, 0xF0|byte(len(streamLoop)>>8), byte(len(streamLoop)))
+	body = append(body, streamLoop...)
+	return newTestSection(NIT_ACTUAL_TABLE_ID, body)
+}
+
+// Returns element of transport stream loop of tsid.
+func newTestTransportStream(tsid uint16, descriptors []byte) []byte {
+	ts := []byte{byte(tsid >> 8), byte(tsid), 0x00, 0x01, 0xF0 | byte(len(descriptors)>>8), byte(len(descriptors))}
+	return append(ts, descriptors...)
+}
+
+func TestParseNitBrokenDescriptor(t *testing.T) {
+	serviceList := []byte{ServiceListTag, 0x03, 0x04, 0x00, 0x01}
+	brokenServiceList := []byte{ServiceListTag, 0x02, 0x04, 0x00}
+	brokenSatellite := []byte{SatelliteDeliverySystemTag, 0x02, 0x00, 0x00}
+
+	cases := []struct {
+		name               string
+		networkDescriptors []byte
+		streamLoop         []byte
+		streams            []int // count of typed descriptors of each transport stream
+		err                bool
+	}{
+		{
+			name:       "valid",
+			streamLoop: append(newTestTransportStream(0x10, serviceList), newTestTransportStream(0x11, serviceList)...),
+			streams:    []int{1, 1},
+		},
+		{
+			name:               "broken network descriptor",
+			networkDescriptors: brokenSatellite,
+			streamLoop:         append(newTestTransportStream(0x10, serviceList), newTestTransportStream(0x11, serviceList)...),
+			streams:            []int{1, 1},
+			err:                true,
+		},
+		{
+			name:       "broken transport descriptor",
+			streamLoop: append(newTestTransportStream(0x10, brokenServiceList), newTestTransportStream(0x11, serviceList)...),
+			streams:    []int{0, 1},
+			err:        true,
+		},
+	}
+	for _, c := range cases {
+		table, err := ParseNit(newTestNit(c.networkDescriptors, c.streamLoop))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParseNit returns %v", c.name, err)
+		}
+		nit, ok := table.(*NITField)
+		if !ok {
+			t.Errorf("%s: ParseNit returns %T, want *NITField", c.name, table)
+			continue
+		}
+		if 0 < len(c.networkDescriptors) {
+			if _, ok := nit.Descriptors[0].(RawDescriptor); !ok {
+				t.Errorf("%s: broken network descriptor is %T, want RawDescriptor", c.name, nit.Descriptors[0])
+			}
+		}
+		if len(c.streams) != len(nit.TransportStreams) {
+			t.Errorf("%s: %d transport streams, want %d", c.name, len(nit.TransportStreams), len(c.streams))
+			continue
+		}
+		for idx, ts := range nit.TransportStreams {
+			typed := 0
+			for _, descriptor := range ts.Descriptors {
+				if _, ok := descriptor.(ServiceListDescriptor); ok {
+					typed++
+				}
+			}
+			if 1 != len(ts.Descriptors) || c.streams[idx] != typed {
+				t.Errorf("%s: TS %d has %d descriptors and %d service lists, want 1 and %d", c.name, idx, len(ts.Descriptors), typed, c.streams[idx])
+			}
+		}
+	}
+}
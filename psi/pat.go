@@ -2,6 +2,7 @@ package psi
 
 import (
 	"encoding/binary"
+	"fmt"
 )
 
 type (
@@ -29,17 +30,24 @@ type (
 const PAT_FIELD_LENGTH = 5
 const PROGRAM_ASSOCIATION_LENGTH = 4
 
+// program_number 0 means Pid is network PID.
+const NETWORK_PROGRAM_NUMBER = 0
+
 func ParsePat(buffer []byte) (interface{}, error) {
-	pointerField := buffer[0]
-	commonTail := (pointerField + 1) + COMMON_FILED_LENGTH // 1 is pointerField size
+	if 0 < len(buffer) && PAT_TABLE_ID != buffer[0] {
+		// Not PAT. (e.g. stuffing table)
+		return nil, nil
+	}
 
 	pat := &PATField{}
-	err := ParseCommon(buffer[(pointerField+1):commonTail], &pat.Common)
+	patBuffer, err := parseSectionHeader(buffer, &pat.Common)
 	if nil != err {
 		return nil, err
 	}
+	if len(patBuffer) < PAT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("PAT section is too short. (section_length is %d)", pat.SectionLength)
+	}
 
-	patBuffer := buffer[commonTail:]
 	pat.TransportStreamId = uint(binary.BigEndian.Uint16(patBuffer[0:2]))
 	pat.reserved2 = patBuffer[2] & 0xC0 >> 6
 	pat.VersionNumber = patBuffer[2] & 0x3E >> 1
@@ -47,8 +55,7 @@ func ParsePat(buffer []byte) (interface{}, error) {
 	pat.SectionNumber = patBuffer[3]
 	pat.LastSectionNumber = patBuffer[4]
 
-	// TODO check PA Field is over buffer
-	paNumber := (pat.SectionLength - (PAT_FIELD_LENGTH + 4)) / PROGRAM_ASSOCIATION_LENGTH
+	paNumber := (pat.SectionLength - (PAT_FIELD_LENGTH + CRC_LENGTH)) / PROGRAM_ASSOCIATION_LENGTH
 	pat.ProgramAssociations = make([]ProgramAssociationField, paNumber)
 	for idx := uint(0); idx < paNumber; idx++ {
 		headIndex := 5 + PROGRAM_ASSOCIATION_LENGTH*idx
@@ -56,17 +63,21 @@ func ParsePat(buffer []byte) (interface{}, error) {
 		pa.ProgramNumber = uint(binary.BigEndian.Uint16(patBuffer[headIndex : headIndex+2]))
 		pa.Reserve = patBuffer[headIndex+2] & 0xE0 >> 5
 		pa.Pid = uint(binary.BigEndian.Uint16([]byte{patBuffer[headIndex+2] & 0x1F, patBuffer[headIndex+3]}))
-
-		// Add PMT's pid to function table
-		_, ok := FunctionTables[pa.Pid]
-		if !ok {
-			FunctionTables[pa.Pid] = ParsePmt
-		}
 	}
 
 	crcHead := 5 + PROGRAM_ASSOCIATION_LENGTH*paNumber
 	pat.Crc = patBuffer[crcHead : crcHead+4]
-	// TODO crc check
 
 	return pat, nil
 }
+
+// ProgramMapPids returns PMT's PIDs which are listed in pat.
+func (pat *PATField) ProgramMapPids() []uint {
+	pids := []uint{}
+	for _, pa := range pat.ProgramAssociations {
+		if NETWORK_PROGRAM_NUMBER != pa.ProgramNumber {
+			pids = append(pids, pa.Pid)
+		}
+	}
+	return pids
+}
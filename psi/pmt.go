@@ -2,6 +2,7 @@ package psi
 
 import (
 	"encoding/binary"
+	"fmt"
 )
 
 type (
@@ -18,8 +19,9 @@ type (
 		reserved4            byte
 		ProgramInfoLength    uint16
 
-		Descriptors []PMTDescriptor
-		Streams     []PMTStream
+		Descriptors   []PMTDescriptor
+		CADescriptors []CADescriptor // ECM of program
+		Streams       []PMTStream
 
 		Crc []byte
 	}
@@ -37,22 +39,32 @@ type (
 		reserved2     byte
 		ESInfoLength  uint16
 		ESInfo        []byte
+
+		Descriptors     []PMTDescriptor
+		CADescriptors   []CADescriptor // ECM of stream
+		Components      []ComponentDescriptor
+		AudioComponents []AudioComponentDescriptor
 	}
 )
 
 const PMT_FIELD_LENGTH = 9
+const PMT_STREAM_FIELD_LENGTH = 5
 
 func ParsePmt(buffer []byte) (interface{}, error) {
-	pointerField := buffer[0]
-	commonTail := (pointerField + 1) + COMMON_FILED_LENGTH // 1 is pointerField size
+	if 0 < len(buffer) && PMT_TABLE_ID != buffer[0] {
+		// Not PMT. (e.g. stuffing table)
+		return nil, nil
+	}
 
 	pmt := &PMTField{}
-	err := ParseCommon(buffer[(pointerField+1):commonTail], &pmt.Common)
+	pmtBuffer, err := parseSectionHeader(buffer, &pmt.Common)
 	if nil != err {
 		return nil, err
 	}
+	if len(pmtBuffer) < PMT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("PMT section is too short. (section_length is %d)", pmt.SectionLength)
+	}
 
-	pmtBuffer := buffer[commonTail:]
 	pmt.ProgramNumber = binary.BigEndian.Uint16(pmtBuffer[0:2])
 	pmt.reserved2 = pmtBuffer[2] & 0xC0 >> 6
 	pmt.VersionNumber = pmtBuffer[2] & 0x3E >> 1
@@ -64,45 +76,149 @@ func ParsePmt(buffer []byte) (interface{}, error) {
 	pmt.reserved4 = pmtBuffer[7] & 0xF0 >> 4
 	pmt.ProgramInfoLength = binary.BigEndian.Uint16([]byte{pmtBuffer[7] & 0x0F, pmtBuffer[8]})
 
-	descriptorTail := PMT_FIELD_LENGTH + pmt.ProgramInfoLength
-	descriptorBuffer := pmtBuffer[PMT_FIELD_LENGTH:descriptorTail]
-	idx := 0
-	for true {
-		descriptor := PMTDescriptor{
-			Tag:    descriptorBuffer[idx],
-			Length: descriptorBuffer[idx+1],
+	streamTail := int(pmt.SectionLength) - CRC_LENGTH
+	descriptorTail := PMT_FIELD_LENGTH + int(pmt.ProgramInfoLength)
+	if streamTail < descriptorTail {
+		return nil, fmt.Errorf("Invalid program_info_length %d. (section_length is %d)", pmt.ProgramInfoLength, pmt.SectionLength)
+	}
+	pmt.Descriptors, err = parsePmtDescriptors(pmtBuffer[PMT_FIELD_LENGTH:descriptorTail])
+	if nil != err {
+		return nil, err
+	}
+	// Error of first broken descriptor, which is returned with pmt.
+	var brokenErr error
+	// NOTE program is kept even if some CA descriptors are broken.
+	pmt.CADescriptors, brokenErr = pickCADescriptors(pmt.Descriptors)
+	if nil != brokenErr {
+		brokenErr = fmt.Errorf("Program 0x%04X has broken descriptor. (%s)", pmt.ProgramNumber, brokenErr.Error())
+	}
+
+	streamBuffer := pmtBuffer[descriptorTail:streamTail]
+	for idx := 0; idx < len(streamBuffer); {
+		if len(streamBuffer) < idx+PMT_STREAM_FIELD_LENGTH {
+			return nil, fmt.Errorf("PMT stream loop is broken. (%d bytes remain)", len(streamBuffer)-idx)
 		}
-		fullSize := idx + 2 + int(descriptor.Length)
-		descriptor.Data = descriptorBuffer[idx+2 : fullSize]
-		idx += fullSize
-		pmt.Descriptors = append(pmt.Descriptors, descriptor)
-		if len(descriptorBuffer) <= idx {
-			break
+		stream := PMTStream{
+			StreamType:    streamBuffer[idx],
+			reserved:      streamBuffer[idx+1] & 0xE0 >> 5,
+			ElementaryPid: binary.BigEndian.Uint16([]byte{streamBuffer[idx+1] & 0x1F, streamBuffer[idx+2]}),
+			reserved2:     streamBuffer[idx+3] & 0xF0 >> 4,
+			ESInfoLength:  binary.BigEndian.Uint16([]byte{streamBuffer[idx+3] & 0x0F, streamBuffer[idx+4]}),
 		}
+		infoHead := idx + PMT_STREAM_FIELD_LENGTH
+		infoTail := infoHead + int(stream.ESInfoLength)
+		if len(streamBuffer) < infoTail {
+			return nil, fmt.Errorf("Invalid ES_info_length %d for PID 0x%04X.", stream.ESInfoLength, stream.ElementaryPid)
+		}
+		stream.ESInfo = streamBuffer[infoHead:infoTail]
+		stream.Descriptors, err = parsePmtDescriptors(stream.ESInfo)
+		if nil != err {
+			return nil, err
+		}
+		// NOTE stream is kept even if some CA or component descriptors are broken.
+		var caErr, componentErr error
+		stream.CADescriptors, caErr = pickCADescriptors(stream.Descriptors)
+		stream.Components, stream.AudioComponents, componentErr = pickComponentDescriptors(stream.Descriptors)
+		if nil == caErr {
+			caErr = componentErr
+		}
+		if nil != caErr && nil == brokenErr {
+			brokenErr = fmt.Errorf("Stream of PID 0x%04X has broken descriptor. (%s)", stream.ElementaryPid, caErr.Error())
+		}
+		pmt.Streams = append(pmt.Streams, stream)
+		idx = infoTail
 	}
 
-	streamTail := pmt.SectionLength - 4
-	// TODO maybe wrong, so need fix
-	//streamSize := int(streamTail - uint(descriptorTail))
-	//streamBuffer := pmtBuffer[descriptorTail:streamTail]
-	//idx = 0
-	//for true {
-	//	stream := PMTStream{
-	//		StreamType:    streamBuffer[idx],
-	//		reserved:      streamBuffer[idx+1] & 0xC0 >> 5,
-	//		ElementaryPid: binary.BigEndian.Uint16([]byte{streamBuffer[idx+1] & 0x1F, streamBuffer[idx+2]}),
-	//		reserved2:     streamBuffer[idx+3] & 0xF0 >> 4,
-	//		ESInfoLength:  binary.BigEndian.Uint16([]byte{streamBuffer[idx+3] & 0x0F, streamBuffer[idx+4]}),
-	//	}
-	//	stream.ESInfo = pmtBuffer[idx+5 : idx+5+int(stream.ESInfoLength)]
-	//	idx += (5 + int(stream.ESInfoLength))
-
-	//	if streamSize <= idx {
-	//		break
-	//	}
-	//}
-
-	// TODO crc check
 	pmt.Crc = pmtBuffer[streamTail:pmt.SectionLength]
-	return pmt, nil
+	return pmt, brokenErr
+}
+
+func parsePmtDescriptors(buffer []byte) (descriptors []PMTDescriptor, err error) {
+	for idx := 0; idx < len(buffer); {
+		if len(buffer) < idx+2 {
+			return nil, fmt.Errorf("Descriptor loop is broken. (%d bytes remain)", len(buffer)-idx)
+		}
+		descriptor := PMTDescriptor{
+			Tag:    buffer[idx],
+			Length: buffer[idx+1],
+		}
+		tail := idx + 2 + int(descriptor.Length)
+		if len(buffer) < tail {
+			return nil, fmt.Errorf("Invalid descriptor length %d for tag 0x%02X.", descriptor.Length, descriptor.Tag)
+		}
+		descriptor.Data = buffer[idx+2 : tail]
+		descriptors = append(descriptors, descriptor)
+		idx = tail
+	}
+	return descriptors, nil
+}
+
+// Pick typed CA descriptors. Broken descriptors are skipped, and the first *DescriptorError is returned.
+// (they are kept in descriptors as PMTDescriptor)
+func pickCADescriptors(descriptors []PMTDescriptor) (caDescriptors []CADescriptor, err error) {
+	for _, descriptor := range descriptors {
+		if CATag != descriptor.Tag {
+			continue
+		}
+		common := DescriptorCommon{
+			Tag:    descriptor.Tag,
+			Length: descriptor.Length,
+		}
+		cad, parseErr := parseCADescriptorBody(common, descriptor.Data)
+		if nil != parseErr {
+			if nil == err {
+				err = &DescriptorError{Tag: descriptor.Tag, Err: parseErr}
+			}
+			continue
+		}
+		caDescriptors = append(caDescriptors, cad)
+	}
+	return caDescriptors, err
+}
+
+// Pick typed component descriptors. Broken descriptors are skipped, and the first *DescriptorError is returned.
+// (they are kept in descriptors as PMTDescriptor)
+func pickComponentDescriptors(descriptors []PMTDescriptor) (components []ComponentDescriptor, audioComponents []AudioComponentDescriptor, err error) {
+	for _, descriptor := range descriptors {
+		common := DescriptorCommon{
+			Tag:    descriptor.Tag,
+			Length: descriptor.Length,
+		}
+		var parsed interface{}
+		var parseErr error
+		switch descriptor.Tag {
+		case ComponentTag:
+			parsed, parseErr = parseComponentDescriptor(common, descriptor.Data)
+		case AudioComponentTag:
+			parsed, parseErr = parseAudioComponentDescriptor(common, descriptor.Data)
+		default:
+			continue
+		}
+		if nil != parseErr {
+			if nil == err {
+				err = &DescriptorError{Tag: descriptor.Tag, Err: parseErr}
+			}
+			continue
+		}
+		switch d := parsed.(type) {
+		case ComponentDescriptor:
+			components = append(components, d)
+		case AudioComponentDescriptor:
+			audioComponents = append(audioComponents, d)
+		}
+	}
+	return components, audioComponents, err
+}
+
+// IsScrambled returns whether program or some stream has CA descriptor.
+func (pmt *PMTField) IsScrambled() bool {
+	if 0 < len(pmt.CADescriptors) {
+		return true
+	}
+	for _, stream := range pmt.Streams {
+		if 0 < len(stream.CADescriptors) {
+			return true
+		}
+	}
+	return false
 }
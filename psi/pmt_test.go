@@ -0,0 +1,103 @@
+package psi
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// Returns section whose section_length and CRC_32 are filled. (body follows section_length)
+func newTestSection(tableId byte, body []byte) []byte {
+	length := len(body) + CRC_LENGTH
+	section := append([]byte{tableId, 0xB0 | byte(length>>8), byte(length)}, body...)
+	section = append(section, make([]byte, CRC_LENGTH)...)
+	binary.BigEndian.PutUint32(section[len(section)-CRC_LENGTH:], Crc32(section[:len(section)-CRC_LENGTH]))
+	return section
+}
+
+// Returns PMT of program_number 1 and PCR_PID 0x0101, which has video stream of esInfo and audio stream.
+func newTestPmt(programInfo []byte, esInfo []byte) []byte {
+	body := []byte{0x00, 0x01, 0xC1, 0x00, 0x00, 0xE1, 0x01, 0xF0 | byte(len(programInfo)>>8), byte(len(programInfo))}
+	body = append(body, programInfo...)
+	body = append(body, 0x02, 0xE1, 0x11, 0xF0|byte(len(esInfo)>>8), byte(len(esInfo)))
+	body = append(body, esInfo...)
+	body = append(body, 0x0F, 0xE1, 0x12, 0xF0, 0x00)
+	return newTestSection(PMT_TABLE_ID, body)
+}
+
+func TestParsePmtBrokenDescriptor(t *testing.T) {
+	component := []byte{ComponentTag, 0x06, 0xF1, 0xB3, 0x00, 'j', 'p', 'n'}
+	shortComponent := []byte{ComponentTag, 0x02, 0xF1, 0xB3}
+	shortAudioComponent := []byte{AudioComponentTag, 0x01, 0xF2}
+
+	cases := []struct {
+		name        string
+		programInfo []byte
+		esInfo      []byte
+		descriptors int
+		components  int
+		err         bool
+	}{
+		{"valid", nil, component, 1, 1, false},
+		{"short component", nil, shortComponent, 1, 0, true},
+		{"short component before valid one", nil, append(append([]byte{}, shortComponent...), component...), 2, 1, true},
+		{"short audio component", nil, shortAudioComponent, 1, 0, true},
+	}
+	for _, c := range cases {
+		table, err := ParsePmt(newTestPmt(c.programInfo, c.esInfo))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParsePmt returns %v", c.name, err)
+		}
+		pmt, ok := table.(*PMTField)
+		if !ok {
+			t.Errorf("%s: ParsePmt returns %T, want *PMTField", c.name, table)
+			continue
+		}
+		if 0x0101 != pmt.PCRPid || 2 != len(pmt.Streams) {
+			t.Errorf("%s: PCR_PID is 0x%04X and %d streams, want 0x0101 and 2", c.name, pmt.PCRPid, len(pmt.Streams))
+			continue
+		}
+		if c.components != len(pmt.Streams[0].Components) {
+			t.Errorf("%s: %d component descriptors, want %d", c.name, len(pmt.Streams[0].Components), c.components)
+		}
+		if c.descriptors != len(pmt.Streams[0].Descriptors) {
+			t.Errorf("%s: %d descriptors, want %d", c.name, len(pmt.Streams[0].Descriptors), c.descriptors)
+		}
+	}
+}
+
+func TestParsePmtBrokenCADescriptor(t *testing.T) {
+	ca := []byte{CATag, 0x04, 0x00, 0x05, 0xE1, 0x20}
+	shortCa := []byte{CATag, 0x02, 0x00, 0x05}
+
+	cases := []struct {
+		name        string
+		programInfo []byte
+		esInfo      []byte
+		programCAs  int
+		streamCAs   int
+		err         bool
+	}{
+		{"valid", ca, ca, 1, 1, false},
+		{"short CA of program", shortCa, ca, 0, 1, true},
+		{"short CA of stream", ca, append(append([]byte{}, shortCa...), ca...), 1, 1, true},
+	}
+	for _, c := range cases {
+		table, err := ParsePmt(newTestPmt(c.programInfo, c.esInfo))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParsePmt returns %v", c.name, err)
+		}
+		pmt, ok := table.(*PMTField)
+		if !ok {
+			t.Errorf("%s: ParsePmt returns %T, want *PMTField", c.name, table)
+			continue
+		}
+		if 0x0101 != pmt.PCRPid || 2 != len(pmt.Streams) {
+			t.Errorf("%s: PCR_PID is 0x%04X and %d streams, want 0x0101 and 2", c.name, pmt.PCRPid, len(pmt.Streams))
+			continue
+		}
+		if c.programCAs != len(pmt.CADescriptors) || c.streamCAs != len(pmt.Streams[0].CADescriptors) {
+			t.Errorf("%s: %d and %d CA descriptors, want %d and %d",
+				c.name, len(pmt.CADescriptors), len(pmt.Streams[0].CADescriptors), c.programCAs, c.streamCAs)
+		}
+	}
+}
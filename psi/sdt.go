@@ -0,0 +1,119 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	SDTField struct {
+		Common
+		TransportStreamId    uint
+		reserved2            byte
+		VersionNumber        byte
+		CurrentNextIndicator bool
+		SectionNumber        byte
+		LastSectionNumber    byte
+		OriginalNetworkId    uint
+		reserved3            byte
+
+		Services []SDTService
+
+		Crc []byte
+	}
+
+	SDTService struct {
+		ServiceId               uint
+		reserved                byte
+		EITUserDefinedFlags     byte
+		EITScheduleFlag         bool
+		EITPresentFollowingFlag bool
+		RunningStatus           byte
+		FreeCAMode              bool
+		DescriptorsLoopLength   uint16
+
+		Descriptors []interface{}
+	}
+)
+
+const SDT_FIELD_LENGTH = 8
+const SDT_SERVICE_FIELD_LENGTH = 5
+
+// ParseSdtBat parses SDT or BAT, which are transmitted on same PID.
+func ParseSdtBat(buffer []byte) (interface{}, error) {
+	if 0 < len(buffer) && BAT_TABLE_ID == buffer[0] {
+		return ParseBat(buffer)
+	}
+	return ParseSdt(buffer)
+}
+
+func ParseSdt(buffer []byte) (interface{}, error) {
+	if 0 < len(buffer) && SDT_ACTUAL_TABLE_ID != buffer[0] && SDT_OTHER_TABLE_ID != buffer[0] {
+		// Not SDT. (e.g. stuffing table)
+		return nil, nil
+	}
+
+	sdt := &SDTField{}
+	sdtBuffer, err := parseSectionHeader(buffer, &sdt.Common)
+	if nil != err {
+		return nil, err
+	}
+	if len(sdtBuffer) < SDT_FIELD_LENGTH+CRC_LENGTH {
+		return nil, fmt.Errorf("SDT section is too short. (section_length is %d)", sdt.SectionLength)
+	}
+
+	sdt.TransportStreamId = uint(binary.BigEndian.Uint16(sdtBuffer[0:2]))
+	sdt.reserved2 = sdtBuffer[2] & 0xC0 >> 6
+	sdt.VersionNumber = sdtBuffer[2] & 0x3E >> 1
+	sdt.CurrentNextIndicator = sdtBuffer[2]&0x01 > 0
+	sdt.SectionNumber = sdtBuffer[3]
+	sdt.LastSectionNumber = sdtBuffer[4]
+	sdt.OriginalNetworkId = uint(binary.BigEndian.Uint16(sdtBuffer[5:7]))
+	sdt.reserved3 = sdtBuffer[7]
+
+	crcHead := int(sdt.SectionLength) - CRC_LENGTH
+	serviceBuffer := sdtBuffer[SDT_FIELD_LENGTH:crcHead]
+	// Error of first broken descriptor, which is returned with sdt.
+	var brokenErr error
+	for idx := 0; idx < len(serviceBuffer); {
+		if len(serviceBuffer) < idx+SDT_SERVICE_FIELD_LENGTH {
+			return nil, fmt.Errorf("SDT service loop is broken. (%d bytes remain)", len(serviceBuffer)-idx)
+		}
+		service := SDTService{
+			ServiceId:               uint(binary.BigEndian.Uint16(serviceBuffer[idx : idx+2])),
+			reserved:                serviceBuffer[idx+2] & 0xE0 >> 5,
+			EITUserDefinedFlags:     serviceBuffer[idx+2] & 0x1C >> 2,
+			EITScheduleFlag:         serviceBuffer[idx+2]&0x02 > 0,
+			EITPresentFollowingFlag: serviceBuffer[idx+2]&0x01 > 0,
+			RunningStatus:           serviceBuffer[idx+3] & 0xE0 >> 5,
+			FreeCAMode:              serviceBuffer[idx+3]&0x10 > 0,
+			DescriptorsLoopLength:   binary.BigEndian.Uint16([]byte{serviceBuffer[idx+3] & 0x0F, serviceBuffer[idx+4]}),
+		}
+		head := idx + SDT_SERVICE_FIELD_LENGTH
+		tail := head + int(service.DescriptorsLoopLength)
+		if len(serviceBuffer) < tail {
+			return nil, fmt.Errorf("Invalid descriptors_loop_length %d for service 0x%04X.", service.DescriptorsLoopLength, service.ServiceId)
+		}
+		// NOTE service is kept even if some descriptors are broken.
+		descriptors, descriptorErr := ParseDescriptors(serviceBuffer[head:tail])
+		service.Descriptors = descriptors
+		if nil != descriptorErr && nil == brokenErr {
+			brokenErr = fmt.Errorf("Service 0x%04X has broken descriptor. (%s)", service.ServiceId, descriptorErr.Error())
+		}
+		sdt.Services = append(sdt.Services, service)
+		idx = tail
+	}
+
+	sdt.Crc = sdtBuffer[crcHead:sdt.SectionLength]
+	return sdt, brokenErr
+}
+
+// ServiceDescriptor returns service descriptor of service.
+func (service *SDTService) ServiceDescriptor() (ServiceDescriptor, bool) {
+	for _, descriptor := range service.Descriptors {
+		if sd, ok := descriptor.(ServiceDescriptor); ok {
+			return sd, true
+		}
+	}
+	return ServiceDescriptor{}, false
+}
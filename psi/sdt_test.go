@@ -0,0 +1,97 @@
+package psi
+
+import (
+	"testing"
+)
+
+// Returns SDT of transport_stream_id 1 and original_network_id 4 which has service loop.
+func newTestSdt(serviceLoop []byte) []byte {
+	body := append([]byte{0x00, 0x01, 0xC1, 0x00, 0x00, 0x00, 0x04, 0xFF}, serviceLoop...)
+	return newTestSection(SDT_ACTUAL_TABLE_ID, body)
+}
+
+// Returns element of service loop of serviceId.
+func newTestService(serviceId uint16, descriptors []byte) []byte {
+	service := []byte{byte(serviceId >> 8), byte(serviceId), 0xFF, 0x80 | byte(len(descriptors)>>8), byte(len(descriptors))}
+	return append(service, descriptors...)
+}
+
+func TestParseSdtBrokenDescriptor(t *testing.T) {
+	service := []byte{ServiceTag, 0x05, 0x01, 0x00, 0x02, 'N', 'a'}
+	brokenService := []byte{ServiceTag, 0x05, 0x01, 0x09, 0x02, 'N', 'a'}
+
+	cases := []struct {
+		name        string
+		serviceLoop []byte
+		names       []string
+		err         bool
+	}{
+		{
+			name:        "valid",
+			serviceLoop: append(newTestService(0x0101, service), newTestService(0x0102, service)...),
+			names:       []string{decodeString([]byte("Na")), decodeString([]byte("Na"))},
+		},
+		{
+			name:        "broken service_provider_name_length",
+			serviceLoop: append(newTestService(0x0101, brokenService), newTestService(0x0102, service)...),
+			names:       []string{"", decodeString([]byte("Na"))},
+			err:         true,
+		},
+	}
+	for _, c := range cases {
+		table, err := ParseSdt(newTestSdt(c.serviceLoop))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParseSdt returns %v", c.name, err)
+		}
+		sdt, ok := table.(*SDTField)
+		if !ok {
+			t.Errorf("%s: ParseSdt returns %T, want *SDTField", c.name, table)
+			continue
+		}
+		if len(c.names) != len(sdt.Services) {
+			t.Errorf("%s: %d services, want %d", c.name, len(sdt.Services), len(c.names))
+			continue
+		}
+		for idx, s := range sdt.Services {
+			sd, _ := s.ServiceDescriptor()
+			if c.names[idx] != sd.ServiceName || 1 != len(s.Descriptors) {
+				t.Errorf("%s: service %d has name %q and %d descriptors, want %q and 1", c.name, idx, sd.ServiceName, len(s.Descriptors), c.names[idx])
+			}
+		}
+	}
+}
+
+func TestParseBatBrokenDescriptor(t *testing.T) {
+	serviceList := []byte{ServiceListTag, 0x03, 0x04, 0x00, 0x01}
+	brokenServiceList := []byte{ServiceListTag, 0x02, 0x04, 0x00}
+
+	cases := []struct {
+		name               string
+		bouquetDescriptors []byte
+		streamLoop         []byte
+		streams            int
+		err                bool
+	}{
+		{"valid", serviceList, newTestTransportStream(0x10, serviceList), 1, false},
+		{"broken bouquet descriptor", brokenServiceList, newTestTransportStream(0x10, serviceList), 1, true},
+		{"broken transport descriptor", serviceList, append(newTestTransportStream(0x10, brokenServiceList), newTestTransportStream(0x11, serviceList)...), 2, true},
+	}
+	for _, c := range cases {
+		// NOTE BAT has same structure as NIT.
+		section := newTestNit(c.bouquetDescriptors, c.streamLoop)
+		section = newTestSection(BAT_TABLE_ID, section[3:len(section)-CRC_LENGTH])
+
+		table, err := ParseSdtBat(section)
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParseSdtBat returns %v", c.name, err)
+		}
+		bat, ok := table.(*BATField)
+		if !ok {
+			t.Errorf("%s: ParseSdtBat returns %T, want *BATField", c.name, table)
+			continue
+		}
+		if 1 != len(bat.Descriptors) || c.streams != len(bat.TransportStreams) {
+			t.Errorf("%s: %d descriptors and %d transport streams, want 1 and %d", c.name, len(bat.Descriptors), len(bat.TransportStreams), c.streams)
+		}
+	}
+}
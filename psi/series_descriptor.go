@@ -0,0 +1,135 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+type (
+	// Descriptor Tag Number : 0xD5
+	// NOTE ExpireDate is zero time if ExpireDateValidFlag is false.
+	SeriesDescriptor struct {
+		DescriptorCommon
+		SeriesId            uint16
+		RepeatLabel         byte
+		ProgramPattern      byte
+		ExpireDateValidFlag bool
+		ExpireDate          time.Time
+		EpisodeNumber       uint16 // 0 means undefined
+		LastEpisodeNumber   uint16 // 0 means undefined
+		SeriesName          string
+	}
+
+	// Descriptor Tag Number : 0xD6
+	// OtherNetworkEvents is set when GroupType is EVENT_GROUP_RELAY_TO_OTHER_NETWORK or EVENT_GROUP_MOVEMENT_FROM_OTHER_NETWORK.
+	EventGroupDescriptor struct {
+		DescriptorCommon
+		GroupType          byte
+		EventCount         byte
+		Events             []EventGroupItem
+		OtherNetworkEvents []EventGroupItem
+		PrivateData        []byte
+	}
+
+	// NOTE OriginalNetworkId and TransportStreamId are set only in OtherNetworkEvents.
+	EventGroupItem struct {
+		OriginalNetworkId uint16
+		TransportStreamId uint16
+		ServiceId         uint16
+		EventId           uint16
+	}
+)
+
+const (
+	SeriesTag     = 0xD5
+	EventGroupTag = 0xD6
+
+	SERIES_FIELD_LENGTH           = 8
+	EVENT_GROUP_ITEM_LENGTH       = 4
+	EVENT_GROUP_OTHER_ITEM_LENGTH = 8
+
+	// program_pattern
+	PROGRAM_PATTERN_IRREGULAR    = 0x0
+	PROGRAM_PATTERN_EVERY_DAY    = 0x1 // including weekends
+	PROGRAM_PATTERN_WEEKDAYS     = 0x2
+	PROGRAM_PATTERN_WEEKLY       = 0x3
+	PROGRAM_PATTERN_MONTHLY      = 0x4
+	PROGRAM_PATTERN_SEVERAL_DAYS = 0x5
+	PROGRAM_PATTERN_DIVISION     = 0x6 // long program divided into several events
+	PROGRAM_PATTERN_UNDEFINED    = 0x7
+
+	// group_type
+	EVENT_GROUP_SHARING                     = 0x1 // same event on several services
+	EVENT_GROUP_RELAY                       = 0x2
+	EVENT_GROUP_MOVEMENT                    = 0x3
+	EVENT_GROUP_RELAY_TO_OTHER_NETWORK      = 0x4
+	EVENT_GROUP_MOVEMENT_FROM_OTHER_NETWORK = 0x5
+)
+
+// REF ARIB STD-B10 第2部 6.2.33
+func parseSeriesDescriptor(common DescriptorCommon, data []byte, loc *time.Location) (interface{}, error) {
+	if len(data) < SERIES_FIELD_LENGTH {
+		return nil, fmt.Errorf("Series descriptor is too short. (%d bytes)", len(data))
+	}
+	sd := SeriesDescriptor{
+		DescriptorCommon:    common,
+		SeriesId:            binary.BigEndian.Uint16(data[0:2]),
+		RepeatLabel:         data[2] & 0xF0 >> 4,
+		ProgramPattern:      data[2] & 0x0E >> 1,
+		ExpireDateValidFlag: data[2]&0x01 > 0,
+		EpisodeNumber:       uint16(data[5])<<4 | uint16(data[6]&0xF0>>4),
+		LastEpisodeNumber:   uint16(data[6]&0x0F)<<8 | uint16(data[7]),
+		SeriesName:          decodeString(data[SERIES_FIELD_LENGTH:]),
+	}
+	if sd.ExpireDateValidFlag {
+		expireDate, err := decodeTime([]byte{data[3], data[4], 0, 0, 0}, loc)
+		if nil != err {
+			return nil, err
+		}
+		sd.ExpireDate = expireDate
+	}
+	return sd, nil
+}
+
+// REF ARIB STD-B10 第2部 6.2.34
+func parseEventGroupDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("Event group descriptor is too short. (%d bytes)", len(data))
+	}
+	egd := EventGroupDescriptor{
+		DescriptorCommon: common,
+		GroupType:        data[0] & 0xF0 >> 4,
+		EventCount:       data[0] & 0x0F,
+	}
+	eventsTail := 1 + int(egd.EventCount)*EVENT_GROUP_ITEM_LENGTH
+	if len(data) < eventsTail {
+		return nil, fmt.Errorf("Invalid event_count %d for event group descriptor.", egd.EventCount)
+	}
+	for idx := 1; idx < eventsTail; idx += EVENT_GROUP_ITEM_LENGTH {
+		item := EventGroupItem{
+			ServiceId: binary.BigEndian.Uint16(data[idx : idx+2]),
+			EventId:   binary.BigEndian.Uint16(data[idx+2 : idx+4]),
+		}
+		egd.Events = append(egd.Events, item)
+	}
+
+	rest := data[eventsTail:]
+	if EVENT_GROUP_RELAY_TO_OTHER_NETWORK != egd.GroupType && EVENT_GROUP_MOVEMENT_FROM_OTHER_NETWORK != egd.GroupType {
+		egd.PrivateData = rest
+		return egd, nil
+	}
+	if 0 != len(rest)%EVENT_GROUP_OTHER_ITEM_LENGTH {
+		return nil, fmt.Errorf("Invalid other network event loop length %d.", len(rest))
+	}
+	for idx := 0; idx < len(rest); idx += EVENT_GROUP_OTHER_ITEM_LENGTH {
+		item := EventGroupItem{
+			OriginalNetworkId: binary.BigEndian.Uint16(rest[idx : idx+2]),
+			TransportStreamId: binary.BigEndian.Uint16(rest[idx+2 : idx+4]),
+			ServiceId:         binary.BigEndian.Uint16(rest[idx+4 : idx+6]),
+			EventId:           binary.BigEndian.Uint16(rest[idx+6 : idx+8]),
+		}
+		egd.OtherNetworkEvents = append(egd.OtherNetworkEvents, item)
+	}
+	return egd, nil
+}
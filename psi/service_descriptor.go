@@ -0,0 +1,59 @@
+package psi
+
+import (
+	"fmt"
+)
+
+type (
+	// Descriptor Tag Number : 0x47
+	BouquetNameDescriptor struct {
+		DescriptorCommon
+		Name string
+	}
+
+	// Descriptor Tag Number : 0x48
+	ServiceDescriptor struct {
+		DescriptorCommon
+		ServiceType               byte
+		ServiceProviderNameLength byte
+		ServiceProviderName       string
+		ServiceNameLength         byte
+		ServiceName               string
+	}
+)
+
+const (
+	BouquetNameTag = 0x47
+	ServiceTag     = 0x48
+)
+
+func parseBouquetNameDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	bnd := BouquetNameDescriptor{
+		DescriptorCommon: common,
+		Name:             decodeString(data),
+	}
+	return bnd, nil
+}
+
+func parseServiceDescriptor(common DescriptorCommon, data []byte) (interface{}, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("Service descriptor is too short. (%d bytes)", len(data))
+	}
+	sd := ServiceDescriptor{
+		DescriptorCommon:          common,
+		ServiceType:               data[0],
+		ServiceProviderNameLength: data[1],
+	}
+	nameHead := 2 + int(sd.ServiceProviderNameLength)
+	if len(data) < nameHead+1 {
+		return nil, fmt.Errorf("Invalid service_provider_name_length %d.", sd.ServiceProviderNameLength)
+	}
+	sd.ServiceProviderName = decodeString(data[2:nameHead])
+	sd.ServiceNameLength = data[nameHead]
+	nameTail := nameHead + 1 + int(sd.ServiceNameLength)
+	if len(data) < nameTail {
+		return nil, fmt.Errorf("Invalid service_name_length %d.", sd.ServiceNameLength)
+	}
+	sd.ServiceName = decodeString(data[nameHead+1 : nameTail])
+	return sd, nil
+}
@@ -3,11 +3,32 @@ package psi
 import (
 	"encoding/binary"
 	"fmt"
+	"time"
 )
 
-var FunctionTables = map[uint]func(buffer []byte) (interface{}, error){}
+// FunctionTables has table functions of well-known PIDs.
+// NOTE this is shared by all parsers, so do not modify it.
+// PMT's PIDs are registered to each parser by PAT.
+var FunctionTables = map[uint]TableFunc{}
+
+// JST is time zone of ARIB. Time fields are decoded in JST by default.
+// (DVB uses UTC, so use functions with time.UTC)
+var JST = time.FixedZone("JST", 9*60*60)
 
 type (
+	// TableFunc parses section.
+	// NOTE it may return table with error when only a part of table is broken. (e.g. descriptor)
+	TableFunc func(buffer []byte) (interface{}, error)
+
+	// Fields which follow section_length in section which has section_syntax_indicator.
+	ExtendedHeader struct {
+		TableIdExtension     uint
+		VersionNumber        byte
+		CurrentNextIndicator bool
+		SectionNumber        byte
+		LastSectionNumber    byte
+	}
+
 	Common struct {
 		TableId                byte
 		SectionSyntaxIndicator bool
@@ -18,14 +39,98 @@ type (
 )
 
 const COMMON_FILED_LENGTH = 3
+const EXTENDED_HEADER_LENGTH = 5
+const CRC_LENGTH = 4
+
+// Table ids
+const (
+	PAT_TABLE_ID = 0x00
+	CAT_TABLE_ID = 0x01
+	PMT_TABLE_ID = 0x02
+
+	NIT_ACTUAL_TABLE_ID = 0x40
+	NIT_OTHER_TABLE_ID  = 0x41
+	SDT_ACTUAL_TABLE_ID = 0x42
+	SDT_OTHER_TABLE_ID  = 0x46
+	BAT_TABLE_ID        = 0x4A
+
+	EIT_PF_ACTUAL_TABLE_ID       = 0x4E
+	EIT_PF_OTHER_TABLE_ID        = 0x4F
+	EIT_SCHEDULE_ACTUAL_TABLE_ID = 0x50 // 0x50 - 0x5F
+	EIT_SCHEDULE_OTHER_TABLE_ID  = 0x60 // 0x60 - 0x6F
+	EIT_SCHEDULE_LAST_TABLE_ID   = 0x6F
+
+	TDT_TABLE_ID = 0x70
+	TOT_TABLE_ID = 0x73
+)
+
+// Well-known PIDs which have table functions.
+const (
+	PAT_PID     = 0x00
+	CAT_PID     = 0x01
+	NIT_PID     = 0x10
+	SDT_BAT_PID = 0x11
+	TDT_TOT_PID = 0x14
+)
+
+// PIDs of EIT. (0x26 and 0x27 are used by ARIB terrestrial broadcasting)
+var EIT_PIDS = []uint{0x12, 0x26, 0x27}
 
 func init() {
-	FunctionTables = map[uint](func(buffer []byte) (interface{}, error)){
-		0x00: ParsePat,
-		0x12: ParseEit,
-		0x26: ParseEit,
-		0x27: ParseEit,
+	FunctionTables = NewFunctionTables(JST)
+}
+
+// NewFunctionTables returns table functions of well-known PIDs whose time fields are decoded in loc.
+func NewFunctionTables(loc *time.Location) map[uint]TableFunc {
+	tables := map[uint]TableFunc{
+		PAT_PID:     ParsePat,
+		CAT_PID:     ParseCat,
+		NIT_PID:     ParseNit,
+		SDT_BAT_PID: ParseSdtBat,
+		TDT_TOT_PID: TdtTotParser(loc),
+	}
+	for _, pid := range EIT_PIDS {
+		tables[pid] = EitParser(loc)
+	}
+	return tables
+}
+
+// Parse common header of section, and returns section body. (after section_length field)
+func parseSectionHeader(buffer []byte, common *Common) ([]byte, error) {
+	if len(buffer) < COMMON_FILED_LENGTH {
+		return nil, fmt.Errorf("Invalid buffer size '%d' for section.", len(buffer))
+	}
+	err := ParseCommon(buffer[:COMMON_FILED_LENGTH], common)
+	if nil != err {
+		return nil, err
+	}
+	tail := COMMON_FILED_LENGTH + int(common.SectionLength)
+	if len(buffer) < tail {
+		return nil, fmt.Errorf("Section is shorter than section_length. (%d bytes but section_length is %d)", len(buffer), common.SectionLength)
+	}
+	return buffer[COMMON_FILED_LENGTH:tail], nil
+}
+
+// HasSectionSyntax returns whether section_syntax_indicator is set. (section has ExtendedHeader)
+func HasSectionSyntax(section []byte) bool {
+	if len(section) < COMMON_FILED_LENGTH {
+		return false
+	}
+	return 0 != section[1]&0x80
+}
+
+// ParseExtendedHeader parses fields from table_id_extension to last_section_number in section.
+func ParseExtendedHeader(section []byte) (header ExtendedHeader, err error) {
+	if len(section) < COMMON_FILED_LENGTH+EXTENDED_HEADER_LENGTH {
+		return header, fmt.Errorf("Invalid buffer size '%d' for extended header.", len(section))
 	}
+	buffer := section[COMMON_FILED_LENGTH:]
+	header.TableIdExtension = uint(binary.BigEndian.Uint16(buffer[0:2]))
+	header.VersionNumber = buffer[2] & 0x3E >> 1
+	header.CurrentNextIndicator = buffer[2]&0x01 > 0
+	header.SectionNumber = buffer[3]
+	header.LastSectionNumber = buffer[4]
+	return header, nil
 }
 
 func ParseCommon(buffer []byte, common *Common) (err error) {
@@ -0,0 +1,80 @@
+package psi
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// Descriptor Tag Number : 0x58
+	LocalTimeOffsetDescriptor struct {
+		DescriptorCommon
+		Offsets []LocalTimeOffset
+	}
+
+	LocalTimeOffset struct {
+		CountryCode             string
+		CountryRegionId         byte
+		reserved                byte
+		LocalTimeOffsetPolarity bool // true means negative offset
+		LocalTimeOffset         time.Duration
+		TimeOfChange            time.Time
+		NextTimeOffset          time.Duration
+	}
+)
+
+const (
+	LocalTimeOffsetTag = 0x58
+
+	LOCAL_TIME_OFFSET_LENGTH = 13
+)
+
+func parseLocalTimeOffsetDescriptor(common DescriptorCommon, data []byte, loc *time.Location) (interface{}, error) {
+	if 0 != len(data)%LOCAL_TIME_OFFSET_LENGTH {
+		return nil, fmt.Errorf("Invalid local time offset descriptor length %d.", len(data))
+	}
+	ltod := LocalTimeOffsetDescriptor{
+		DescriptorCommon: common,
+	}
+	for idx := 0; idx < len(data); idx += LOCAL_TIME_OFFSET_LENGTH {
+		timeOfChange, err := decodeTime(data[idx+6:idx+11], loc)
+		if nil != err {
+			return nil, err
+		}
+		offset := LocalTimeOffset{
+			CountryCode:             string(data[idx : idx+3]),
+			CountryRegionId:         data[idx+3] & 0xFC >> 2,
+			reserved:                data[idx+3] & 0x02 >> 1,
+			LocalTimeOffsetPolarity: data[idx+3]&0x01 > 0,
+			LocalTimeOffset:         decodeOffset(data[idx+4 : idx+6]),
+			TimeOfChange:            timeOfChange,
+			NextTimeOffset:          decodeOffset(data[idx+11 : idx+13]),
+		}
+		ltod.Offsets = append(ltod.Offsets, offset)
+	}
+	return ltod, nil
+}
+
+// Offset returns signed local time offset at t.
+func (offset LocalTimeOffset) Offset(t time.Time) time.Duration {
+	duration := offset.LocalTimeOffset
+	if !t.Before(offset.TimeOfChange) {
+		duration = offset.NextTimeOffset
+	}
+	if offset.LocalTimeOffsetPolarity {
+		return -duration
+	}
+	return duration
+}
+
+// Location returns fixed time zone which has offset at t.
+func (offset LocalTimeOffset) Location(t time.Time) *time.Location {
+	return time.FixedZone(offset.CountryCode, int(offset.Offset(t)/time.Second))
+}
+
+// Decode 4 digits BCD of hour and minute.
+func decodeOffset(buffer []byte) time.Duration {
+	hour := decodeBCD(buffer[0:1], 2)
+	minute := decodeBCD(buffer[1:2], 2)
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+}
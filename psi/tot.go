@@ -0,0 +1,135 @@
+package psi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+type (
+	TDTField struct {
+		Common
+		Time time.Time
+	}
+
+	TOTField struct {
+		Common
+		Time                  time.Time
+		reserved2             byte
+		DescriptorsLoopLength uint16
+		Descriptors           []interface{}
+
+		Crc []byte
+	}
+)
+
+const TIME_FIELD_LENGTH = 5
+
+// ParseTdtTot parses TDT or TOT, which are transmitted on same PID. Time is decoded in JST.
+func ParseTdtTot(buffer []byte) (interface{}, error) {
+	return parseTdtTot(buffer, JST)
+}
+
+// TdtTotParser returns table function of TDT and TOT which decodes time in loc.
+func TdtTotParser(loc *time.Location) TableFunc {
+	return func(buffer []byte) (interface{}, error) {
+		return parseTdtTot(buffer, loc)
+	}
+}
+
+func parseTdtTot(buffer []byte, loc *time.Location) (interface{}, error) {
+	if 0 < len(buffer) && TOT_TABLE_ID == buffer[0] {
+		return parseTot(buffer, loc)
+	}
+	return parseTdt(buffer, loc)
+}
+
+func ParseTdt(buffer []byte) (interface{}, error) {
+	return parseTdt(buffer, JST)
+}
+
+func ParseTot(buffer []byte) (interface{}, error) {
+	return parseTot(buffer, JST)
+}
+
+func parseTdt(buffer []byte, loc *time.Location) (interface{}, error) {
+	if 0 < len(buffer) && TDT_TABLE_ID != buffer[0] {
+		// Not TDT. (e.g. stuffing table)
+		return nil, nil
+	}
+
+	tdt := &TDTField{}
+	tdtBuffer, err := parseSectionHeader(buffer, &tdt.Common)
+	if nil != err {
+		return nil, err
+	}
+	if len(tdtBuffer) < TIME_FIELD_LENGTH {
+		return nil, fmt.Errorf("TDT section is too short. (section_length is %d)", tdt.SectionLength)
+	}
+	tdt.Time, err = decodeTime(tdtBuffer[0:TIME_FIELD_LENGTH], loc)
+	if nil != err {
+		return nil, err
+	}
+	return tdt, nil
+}
+
+func parseTot(buffer []byte, loc *time.Location) (interface{}, error) {
+	if 0 < len(buffer) && TOT_TABLE_ID != buffer[0] {
+		// Not TOT. (e.g. stuffing table)
+		return nil, nil
+	}
+
+	tot := &TOTField{}
+	totBuffer, err := parseSectionHeader(buffer, &tot.Common)
+	if nil != err {
+		return nil, err
+	}
+	if len(totBuffer) < TIME_FIELD_LENGTH+2+CRC_LENGTH {
+		return nil, fmt.Errorf("TOT section is too short. (section_length is %d)", tot.SectionLength)
+	}
+
+	tot.Time, err = decodeTime(totBuffer[0:TIME_FIELD_LENGTH], loc)
+	if nil != err {
+		return nil, err
+	}
+	tot.reserved2 = totBuffer[5] & 0xF0 >> 4
+	tot.DescriptorsLoopLength = binary.BigEndian.Uint16([]byte{totBuffer[5] & 0x0F, totBuffer[6]})
+	crcHead := int(tot.SectionLength) - CRC_LENGTH
+	descriptorTail := TIME_FIELD_LENGTH + 2 + int(tot.DescriptorsLoopLength)
+	if crcHead < descriptorTail {
+		return nil, fmt.Errorf("Invalid descriptors_loop_length %d. (section_length is %d)", tot.DescriptorsLoopLength, tot.SectionLength)
+	}
+	// NOTE TOT is kept even if some descriptors are broken, because UTC_time is valid.
+	var brokenErr error
+	tot.Descriptors, err = parseDescriptors(totBuffer[TIME_FIELD_LENGTH+2:descriptorTail], loc)
+	if nil != err {
+		brokenErr = fmt.Errorf("TOT has broken descriptor. (%s)", err.Error())
+	}
+
+	tot.Crc = totBuffer[crcHead:tot.SectionLength]
+	return tot, brokenErr
+}
+
+// LocalTimeOffsets returns offsets in local time offset descriptors.
+func (tot *TOTField) LocalTimeOffsets() []LocalTimeOffset {
+	offsets := []LocalTimeOffset{}
+	for _, descriptor := range tot.Descriptors {
+		if ltod, ok := descriptor.(LocalTimeOffsetDescriptor); ok {
+			offsets = append(offsets, ltod.Offsets...)
+		}
+	}
+	return offsets
+}
+
+// LocalLocation returns time zone of countryCode which is derived from local time offset descriptor at t.
+// NOTE offset is added to UTC. (ETSI EN 300 468)
+// Parser does not apply it, because time fields are encoded in zone of broadcast standard. (JST or UTC)
+// Callers convert decoded time to local time by time.Time.In. (e.g. event.StartTime.In(loc))
+func (tot *TOTField) LocalLocation(countryCode string, t time.Time) (*time.Location, bool) {
+	for _, offset := range tot.LocalTimeOffsets() {
+		if countryCode == offset.CountryCode {
+			return offset.Location(t), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,51 @@
+package psi
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// Returns TOT of 1993-10-13 12:45:00 which has descriptors.
+func newTestTot(descriptors []byte) []byte {
+	body := []byte{0xC0, 0x79, 0x12, 0x45, 0x00, 0xF0 | byte(len(descriptors)>>8), byte(len(descriptors))}
+	body = append(body, descriptors...)
+	length := len(body) + CRC_LENGTH
+	// NOTE TOT does not have section_syntax_indicator.
+	section := append([]byte{TOT_TABLE_ID, 0x30 | byte(length>>8), byte(length)}, body...)
+	section = append(section, make([]byte, CRC_LENGTH)...)
+	binary.BigEndian.PutUint32(section[len(section)-CRC_LENGTH:], Crc32(section[:len(section)-CRC_LENGTH]))
+	return section
+}
+
+func TestParseTotBrokenDescriptor(t *testing.T) {
+	offset := []byte{LocalTimeOffsetTag, 0x0D, 'J', 'P', 'N', 0x02, 0x09, 0x00, 0xC0, 0x79, 0x00, 0x00, 0x00, 0x09, 0x00}
+	brokenOffset := []byte{LocalTimeOffsetTag, 0x02, 'J', 'P'}
+
+	cases := []struct {
+		name        string
+		descriptors []byte
+		location    bool
+		err         bool
+	}{
+		{"valid", offset, true, false},
+		{"broken local time offset descriptor", brokenOffset, false, true},
+	}
+	for _, c := range cases {
+		table, err := ParseTot(newTestTot(c.descriptors))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParseTot returns %v", c.name, err)
+		}
+		tot, ok := table.(*TOTField)
+		if !ok {
+			t.Errorf("%s: ParseTot returns %T, want *TOTField", c.name, table)
+			continue
+		}
+		if !tot.Time.Equal(time.Date(1993, 10, 13, 12, 45, 0, 0, JST)) || 1 != len(tot.Descriptors) {
+			t.Errorf("%s: TOT has time %v and %d descriptors", c.name, tot.Time, len(tot.Descriptors))
+		}
+		if _, ok := tot.LocalLocation("JPN", tot.Time); c.location != ok {
+			t.Errorf("%s: LocalLocation returns %v, want %v", c.name, ok, c.location)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package mpeg2ts
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type (
+	// PacketReader reads ts packets from stream with keeping synchronization to sync byte.
+	PacketReader struct {
+		reader     *bufio.Reader
+		offset     int64
+		synced     bool
+		packetSize int
+
+		skippedBytes int64
+
+		// OnResync is called when some bytes are skipped to find sync byte.
+		OnResync func(event ResyncEvent)
+	}
+
+	ResyncEvent struct {
+		Offset  int64 // stream offset of first skipped byte
+		Skipped int64 // skipped byte size
+	}
+)
+
+const (
+	SYNC_BYTE = 0x47
+
+	// Number of consecutive sync bytes needed to lock synchronization.
+	SYNC_LOCK_COUNT = 5
+
+	READER_BUFFER_SIZE = 64 * 1024
+)
+
+// Packet sizes which are tried in auto detection.
+var packetSizes = []int{PACKET_SIZE, M2TS_PACKET_SIZE, RS_PACKET_SIZE}
+
+// NewPacketReader returns PacketReader which reads packetSize byte packets.
+// If packetSize is 0, packet size is detected from stream.
+func NewPacketReader(reader io.Reader, packetSize int) (r *PacketReader, err error) {
+	if 0 != packetSize && 0 > syncOffset(packetSize) {
+		return nil, fmt.Errorf("Unsupported packet size %d.", packetSize)
+	}
+	r = &PacketReader{
+		reader:     bufio.NewReaderSize(reader, READER_BUFFER_SIZE),
+		packetSize: packetSize,
+	}
+	return r, nil
+}
+
+// ReadPacket reads one packet and returns it with stream offset of its head.
+// Returned buffer contains TP_extra_header or parity bytes by packet size.
+// It returns io.EOF when stream is finished, and io.ErrUnexpectedEOF when last packet is truncated.
+// (truncated bytes are discarded, and they are not reported to OnResync)
+func (r *PacketReader) ReadPacket() (buffer []byte, offset int64, err error) {
+	peekSize := r.packetSize
+	if 0 == peekSize {
+		peekSize = 1
+	}
+	head, err := r.reader.Peek(peekSize)
+	if nil != err && io.EOF != err {
+		return nil, r.offset, err
+	}
+	if 0 == len(head) {
+		return nil, r.offset, io.EOF
+	}
+	if r.synced && len(head) < peekSize && r.isTruncated(head) {
+		offset = r.offset
+		discarded, _ := r.reader.Discard(len(head))
+		r.offset += int64(discarded)
+		return nil, offset, io.ErrUnexpectedEOF
+	}
+
+	if !r.synced || len(head) < peekSize || SYNC_BYTE != head[syncOffset(r.packetSize)] {
+		r.synced = false
+		err = r.synchronize()
+		if nil != err {
+			return nil, r.offset, err
+		}
+	}
+
+	offset = r.offset
+	buffer = make([]byte, r.packetSize)
+	_, err = io.ReadFull(r.reader, buffer)
+	if nil != err {
+		return nil, offset, err
+	}
+	r.offset += int64(r.packetSize)
+	return buffer, offset, nil
+}
+
+// PacketSize returns size of packet. (0 means not detected yet)
+func (r *PacketReader) PacketSize() int {
+	return r.packetSize
+}
+
+// SkippedBytes returns total size of bytes skipped for synchronization.
+func (r *PacketReader) SkippedBytes() int64 {
+	return r.skippedBytes
+}
+
+// Returns whether remain bytes at the end of stream are head of packet. (not garbage)
+func (r *PacketReader) isTruncated(head []byte) bool {
+	idx := syncOffset(r.packetSize)
+	return len(head) <= idx || SYNC_BYTE == head[idx]
+}
+
+// Skip bytes until SYNC_LOCK_COUNT sync bytes are found at packet intervals.
+// If packet size is not decided, each size of packetSizes is tried.
+// NOTE near the end of stream, fewer sync bytes are accepted.
+func (r *PacketReader) synchronize() (err error) {
+	event := ResyncEvent{
+		Offset: r.offset,
+	}
+	defer func() {
+		if 0 < event.Skipped {
+			r.skippedBytes += event.Skipped
+			if nil != r.OnResync {
+				r.OnResync(event)
+			}
+		}
+	}()
+
+	candidateSizes := packetSizes
+	if 0 != r.packetSize {
+		candidateSizes = []int{r.packetSize}
+	}
+	maxSize := 0
+	maxSyncOffset := 0
+	for _, size := range candidateSizes {
+		if maxSize < size {
+			maxSize = size
+		}
+		if maxSyncOffset < syncOffset(size) {
+			maxSyncOffset = syncOffset(size)
+		}
+	}
+
+	for !r.synced {
+		candidate, peekErr := r.reader.Peek(maxSize*(SYNC_LOCK_COUNT-1) + maxSyncOffset + 1)
+		if nil != peekErr && io.EOF != peekErr {
+			return peekErr
+		}
+
+		for _, size := range candidateSizes {
+			if isSynchronized(candidate, size) {
+				r.packetSize = size
+				r.synced = true
+				break
+			}
+		}
+		if r.synced {
+			break
+		}
+
+		if len(candidate) < maxSize && io.EOF == peekErr {
+			// Remain bytes are not enough for any packet.
+			discarded, _ := r.reader.Discard(len(candidate))
+			r.offset += int64(discarded)
+			event.Skipped += int64(discarded)
+			return io.EOF
+		}
+
+		// Skip until next sync byte comes to the sync position of some packet size.
+		skipSize := len(candidate) - maxSyncOffset
+		next := bytes.IndexByte(candidate[1:], SYNC_BYTE)
+		if 0 <= next {
+			skipSize = next + 1 - maxSyncOffset
+			if 0 >= skipSize {
+				skipSize = next + 1
+			}
+		}
+		if 0 >= skipSize {
+			skipSize = 1
+		}
+		discarded, discardErr := r.reader.Discard(skipSize)
+		r.offset += int64(discarded)
+		event.Skipped += int64(discarded)
+		if nil != discardErr {
+			return discardErr
+		}
+	}
+	return nil
+}
+
+// Check sync bytes at each packetSize interval.
+func isSynchronized(buffer []byte, packetSize int) bool {
+	if len(buffer) < packetSize {
+		return false
+	}
+	for idx := syncOffset(packetSize); idx < len(buffer); idx += packetSize {
+		if SYNC_BYTE != buffer[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns position of sync byte in packet. (-1 means unsupported packet size)
+func syncOffset(packetSize int) int {
+	switch packetSize {
+	case PACKET_SIZE, RS_PACKET_SIZE:
+		return 0
+	case M2TS_PACKET_SIZE:
+		return TP_EXTRA_HEADER_LENGTH
+	}
+	return -1
+}
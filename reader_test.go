@@ -0,0 +1,90 @@
+package mpeg2ts
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// Returns count packets of packetSize whose sync bytes are placed at sync position.
+func newTestStream(packetSize int, count int) []byte {
+	stream := []byte{}
+	for idx := 0; idx < count; idx++ {
+		packet := make([]byte, packetSize)
+		packet[syncOffset(packetSize)] = SYNC_BYTE
+		packet[syncOffset(packetSize)+1] = 0x1F
+		packet[syncOffset(packetSize)+2] = 0xFF
+		packet[syncOffset(packetSize)+3] = 0x10
+		stream = append(stream, packet...)
+	}
+	return stream
+}
+
+func TestPacketReaderResync(t *testing.T) {
+	garbage := []byte{0x00, 0x47, 0x12, 0x34, 0x47}
+
+	cases := []struct {
+		name       string
+		packetSize int // 0 means auto detection
+		streamSize int
+		stream     []byte
+		packets    int
+		skipped    int64
+		truncated  bool
+	}{
+		{"188", 0, PACKET_SIZE, newTestStream(PACKET_SIZE, 10), 10, 0, false},
+		{"192", 0, M2TS_PACKET_SIZE, newTestStream(M2TS_PACKET_SIZE, 10), 10, 0, false},
+		{"204", 0, RS_PACKET_SIZE, newTestStream(RS_PACKET_SIZE, 10), 10, 0, false},
+		{"188 after garbage", 0, PACKET_SIZE, append(append([]byte{}, garbage...), newTestStream(PACKET_SIZE, 10)...), 10, 5, false},
+		{"192 after garbage", 0, M2TS_PACKET_SIZE, append(append([]byte{}, garbage...), newTestStream(M2TS_PACKET_SIZE, 10)...), 10, 5, false},
+		{"204 after garbage", 0, RS_PACKET_SIZE, append(append([]byte{}, garbage...), newTestStream(RS_PACKET_SIZE, 10)...), 10, 5, false},
+		{
+			"188 with garbage between packets", PACKET_SIZE, PACKET_SIZE,
+			append(append(newTestStream(PACKET_SIZE, 6), garbage...), newTestStream(PACKET_SIZE, 6)...), 12, 5, false,
+		},
+		{
+			"204 with garbage between packets", RS_PACKET_SIZE, RS_PACKET_SIZE,
+			append(append(newTestStream(RS_PACKET_SIZE, 6), garbage...), newTestStream(RS_PACKET_SIZE, 6)...), 12, 5, false,
+		},
+		{"188 truncated", 0, PACKET_SIZE, newTestStream(PACKET_SIZE, 11)[:10*PACKET_SIZE+100], 10, 0, true},
+		{"192 truncated", 0, M2TS_PACKET_SIZE, newTestStream(M2TS_PACKET_SIZE, 11)[:10*M2TS_PACKET_SIZE+100], 10, 0, true},
+	}
+	for _, c := range cases {
+		reader, err := NewPacketReader(bytes.NewReader(c.stream), c.packetSize)
+		if nil != err {
+			t.Fatalf("%s: NewPacketReader returns %v", c.name, err)
+		}
+		skipped := int64(0)
+		reader.OnResync = func(event ResyncEvent) {
+			skipped += event.Skipped
+		}
+
+		packets := 0
+		truncated := false
+		for {
+			buffer, _, err := reader.ReadPacket()
+			if io.EOF == err {
+				break
+			}
+			if io.ErrUnexpectedEOF == err {
+				truncated = true
+				continue
+			}
+			if nil != err {
+				t.Fatalf("%s: ReadPacket returns %v", c.name, err)
+			}
+			if c.streamSize != len(buffer) || SYNC_BYTE != buffer[syncOffset(c.streamSize)] {
+				t.Errorf("%s: packet %d is not synchronized", c.name, packets)
+			}
+			packets++
+		}
+
+		if c.streamSize != reader.PacketSize() {
+			t.Errorf("%s: packet size is %d, want %d", c.name, reader.PacketSize(), c.streamSize)
+		}
+		if c.packets != packets || c.skipped != skipped || c.skipped != reader.SkippedBytes() || c.truncated != truncated {
+			t.Errorf("%s: %d packets, %d skipped bytes and truncated %v, want %d, %d and %v",
+				c.name, packets, skipped, truncated, c.packets, c.skipped, c.truncated)
+		}
+	}
+}
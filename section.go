@@ -0,0 +1,100 @@
+package mpeg2ts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	// SectionAssembler assembles PSI/SI sections from payloads of one PID.
+	SectionAssembler struct {
+		buffer  []byte
+		started bool
+	}
+)
+
+const (
+	SECTION_HEADER_LENGTH = 3
+	MAX_SECTION_LENGTH    = 4093 // max value of section_length
+	STUFFING_BYTE         = 0xFF
+)
+
+func NewSectionAssembler() *SectionAssembler {
+	return &SectionAssembler{}
+}
+
+// Push adds payload of packet, and returns sections which are completed by the payload.
+// Each returned section starts at table_id and ends at the end of section. (including CRC)
+func (a *SectionAssembler) Push(payload []byte, unitStart bool) (sections [][]byte, err error) {
+	if !unitStart {
+		if !a.started {
+			// Wait for first payload_unit_start_indicator.
+			return nil, nil
+		}
+		a.buffer = append(a.buffer, payload...)
+		return a.extract()
+	}
+
+	if 0 == len(payload) {
+		a.Reset()
+		return nil, fmt.Errorf("Empty payload which has payload_unit_start_indicator.")
+	}
+	pointerField := int(payload[0])
+	if len(payload) < 1+pointerField {
+		a.Reset()
+		return nil, fmt.Errorf("Invalid pointer_field %d. (payload is %d bytes)", pointerField, len(payload))
+	}
+
+	// Bytes before pointer are tail of previous section.
+	if a.started {
+		a.buffer = append(a.buffer, payload[1:1+pointerField]...)
+		sections, err = a.extract()
+		if nil == err && 0 < len(a.buffer) {
+			err = fmt.Errorf("Section is broken. (%d bytes remain when next section starts)", len(a.buffer))
+		}
+	}
+
+	a.buffer = append([]byte{}, payload[1+pointerField:]...)
+	a.started = true
+	newSections, extractErr := a.extract()
+	if nil == err {
+		err = extractErr
+	}
+	return append(sections, newSections...), err
+}
+
+// Reset drops assembling section. (e.g. when packet is dropped)
+func (a *SectionAssembler) Reset() {
+	a.buffer = nil
+	a.started = false
+}
+
+// Pick complete sections from head of buffer.
+func (a *SectionAssembler) extract() (sections [][]byte, err error) {
+	for 0 < len(a.buffer) {
+		if STUFFING_BYTE == a.buffer[0] {
+			// Remain bytes in this packet are stuffing.
+			a.Reset()
+			break
+		}
+		if len(a.buffer) < SECTION_HEADER_LENGTH {
+			break
+		}
+
+		sectionLength := int(binary.BigEndian.Uint16([]byte{a.buffer[1] & 0x0F, a.buffer[2]}))
+		if MAX_SECTION_LENGTH < sectionLength {
+			a.Reset()
+			return sections, fmt.Errorf("Invalid section_length %d.", sectionLength)
+		}
+		tail := SECTION_HEADER_LENGTH + sectionLength
+		if len(a.buffer) < tail {
+			break
+		}
+
+		section := make([]byte, tail)
+		copy(section, a.buffer[:tail])
+		sections = append(sections, section)
+		a.buffer = a.buffer[tail:]
+	}
+	return sections, nil
+}
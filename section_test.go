@@ -0,0 +1,102 @@
+package mpeg2ts
+
+import (
+	"bytes"
+	"testing"
+)
+
+type sectionPayload struct {
+	payload   []byte
+	unitStart bool
+}
+
+// Returns section of tableId whose section_length is length. (body is filled with fill)
+func newTestSection(tableId byte, length int, fill byte) []byte {
+	section := []byte{tableId, 0xB0 | byte(length>>8), byte(length)}
+	return append(section, bytes.Repeat([]byte{fill}, length)...)
+}
+
+func TestSectionAssemblerPush(t *testing.T) {
+	first := newTestSection(0x42, 200, 0x11)
+	second := newTestSection(0x46, 20, 0x22)
+	third := newTestSection(0x4A, 10, 0x33)
+	stuffing := bytes.Repeat([]byte{STUFFING_BYTE}, 8)
+
+	cases := []struct {
+		name     string
+		payloads []sectionPayload
+		sections [][]byte
+		err      bool
+	}{
+		{
+			name: "one section",
+			payloads: []sectionPayload{
+				{append(append([]byte{0x00}, second...), stuffing...), true},
+			},
+			sections: [][]byte{second},
+		},
+		{
+			name: "multiple sections in packet",
+			payloads: []sectionPayload{
+				{append(append(append([]byte{0x00}, second...), third...), stuffing...), true},
+			},
+			sections: [][]byte{second, third},
+		},
+		{
+			name: "section spanning packets",
+			payloads: []sectionPayload{
+				{append([]byte{0x00}, first[:183]...), true},
+				{append(append([]byte{}, first[183:]...), stuffing...), false},
+			},
+			sections: [][]byte{first},
+		},
+		{
+			name: "section after pointer_field",
+			payloads: []sectionPayload{
+				{append([]byte{0x00}, first[:183]...), true},
+				{append(append(append([]byte{byte(len(first) - 183)}, first[183:]...), second...), stuffing...), true},
+			},
+			sections: [][]byte{first, second},
+		},
+		{
+			name: "wait for payload_unit_start_indicator",
+			payloads: []sectionPayload{
+				{first[183:], false},
+				{append(append(append([]byte{byte(len(first) - 183)}, first[183:]...), third...), stuffing...), true},
+			},
+			sections: [][]byte{third},
+		},
+		{
+			name: "invalid pointer_field",
+			payloads: []sectionPayload{
+				{[]byte{0x10, 0x00, 0x00}, true},
+			},
+			err: true,
+		},
+	}
+	for _, c := range cases {
+		assembler := NewSectionAssembler()
+		sections := [][]byte{}
+		var err error
+		for _, p := range c.payloads {
+			s, pushErr := assembler.Push(p.payload, p.unitStart)
+			sections = append(sections, s...)
+			if nil == err {
+				err = pushErr
+			}
+		}
+		if c.err != (nil != err) {
+			t.Errorf("%s: Push returns %v", c.name, err)
+			continue
+		}
+		if len(c.sections) != len(sections) {
+			t.Errorf("%s: %d sections are assembled, want %d", c.name, len(sections), len(c.sections))
+			continue
+		}
+		for idx := range sections {
+			if !bytes.Equal(c.sections[idx], sections[idx]) {
+				t.Errorf("%s: section %d is % X, want % X", c.name, idx, sections[idx], c.sections[idx])
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package mpeg2ts
+
+import (
+	"encoding/binary"
+
+	"mpeg2ts/psi"
+)
+
+type (
+	// TableCache collects sections of each table and suppresses repeated sections.
+	// Table is identified by TableKey, and version is decided by version_number.
+	TableCache struct {
+		tables map[TableKey]*cachedTable
+	}
+
+	TableKey struct {
+		Pid              uint
+		TableId          byte
+		TableIdExtension uint
+		// Only EIT has TransportStreamId in key. (table_id_extension of EIT is service_id)
+		TransportStreamId uint
+		// Only EIT and SDT have OriginalNetworkId in key. (table_id_extension of SDT is transport_stream_id)
+		OriginalNetworkId uint
+	}
+
+	// TableChange is notified when version of completed table is changed.
+	TableChange struct {
+		Key        TableKey
+		OldVersion byte
+		NewVersion byte
+	}
+
+	cachedTable struct {
+		version           byte
+		lastSectionNumber byte
+		sections          map[byte]interface{}
+
+		completed        bool
+		hasCompleted     bool // whether some version was completed ever
+		completedVersion byte
+	}
+)
+
+func NewTableCache() *TableCache {
+	return &TableCache{
+		tables: map[TableKey]*cachedTable{},
+	}
+}
+
+func NewTableKey(pid uint, section []byte, header psi.ExtendedHeader) TableKey {
+	key := TableKey{
+		Pid:              pid,
+		TableId:          section[0],
+		TableIdExtension: header.TableIdExtension,
+	}
+	if psi.IsEitTableId(key.TableId) && len(section) >= 12 {
+		key.TransportStreamId = uint(binary.BigEndian.Uint16(section[8:10]))
+		key.OriginalNetworkId = uint(binary.BigEndian.Uint16(section[10:12]))
+	}
+	if isSdtTableId(key.TableId) && len(section) >= 10 {
+		key.OriginalNetworkId = uint(binary.BigEndian.Uint16(section[8:10]))
+	}
+	return key
+}
+
+// RemovePid removes all tables of pid.
+func (c *TableCache) RemovePid(pid uint) {
+	for key := range c.tables {
+		if pid == key.Pid {
+			delete(c.tables, key)
+		}
+	}
+}
+
+// IsRepeated returns whether the section of this version is already received.
+func (c *TableCache) IsRepeated(key TableKey, header psi.ExtendedHeader) bool {
+	table, ok := c.tables[key]
+	if !ok || table.version != header.VersionNumber {
+		return false
+	}
+	_, ok = table.sections[header.SectionNumber]
+	return ok
+}
+
+// Push stores parsed section, and returns sections which should be delivered.
+// Sections are returned in order of section_number when all sections of the table are collected.
+// NOTE each EIT section is returned as soon as it is received, because sections of EIT are
+// divided into segments and each section has complete events.
+func (c *TableCache) Push(key TableKey, header psi.ExtendedHeader, section interface{}) (deliver []interface{}, change *TableChange) {
+	if header.LastSectionNumber < header.SectionNumber {
+		return nil, nil
+	}
+
+	table, ok := c.tables[key]
+	if !ok {
+		table = &cachedTable{}
+		c.tables[key] = table
+	}
+	if nil == table.sections || table.version != header.VersionNumber || table.lastSectionNumber != header.LastSectionNumber {
+		table.version = header.VersionNumber
+		table.lastSectionNumber = header.LastSectionNumber
+		table.sections = map[byte]interface{}{}
+		table.completed = false
+	}
+
+	if psi.IsEitTableId(key.TableId) {
+		// Sections of EIT are not kept.
+		table.sections[header.SectionNumber] = nil
+		change = table.complete(key)
+		return []interface{}{section}, change
+	}
+
+	table.sections[header.SectionNumber] = section
+	if table.completed || len(table.sections) <= int(table.lastSectionNumber) {
+		return nil, nil
+	}
+	for number := 0; number <= int(table.lastSectionNumber); number++ {
+		deliver = append(deliver, table.sections[byte(number)])
+	}
+
+	return deliver, table.complete(key)
+}
+
+// Mark current version as completed, and returns change from previous completed version.
+func (table *cachedTable) complete(key TableKey) (change *TableChange) {
+	table.completed = true
+	if table.hasCompleted && table.completedVersion != table.version {
+		change = &TableChange{
+			Key:        key,
+			OldVersion: table.completedVersion,
+			NewVersion: table.version,
+		}
+	}
+	table.hasCompleted = true
+	table.completedVersion = table.version
+	return change
+}
+
+func isSdtTableId(tableId byte) bool {
+	return psi.SDT_ACTUAL_TABLE_ID == tableId || psi.SDT_OTHER_TABLE_ID == tableId
+}
@@ -0,0 +1,218 @@
+package mpeg2ts
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"mpeg2ts/psi"
+)
+
+type cachePush struct {
+	version       byte
+	sectionNumber byte
+	lastNumber    byte
+}
+
+func TestTableCachePush(t *testing.T) {
+	cases := []struct {
+		name     string
+		tableId  byte
+		pushes   []cachePush
+		delivers [][]interface{}
+		changes  []*TableChange
+	}{
+		{
+			name:     "single section",
+			tableId:  psi.SDT_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 0, 0}},
+			delivers: [][]interface{}{{"v0s0"}},
+			changes:  []*TableChange{nil},
+		},
+		{
+			name:     "multi section in order of section_number",
+			tableId:  psi.SDT_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 2, 2}, {0, 0, 2}, {0, 1, 2}},
+			delivers: [][]interface{}{nil, nil, {"v0s0", "v0s1", "v0s2"}},
+			changes:  []*TableChange{nil, nil, nil},
+		},
+		{
+			name:     "completed table is not delivered again",
+			tableId:  psi.SDT_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 0, 1}, {0, 1, 1}, {0, 1, 1}},
+			delivers: [][]interface{}{nil, {"v0s0", "v0s1"}, nil},
+			changes:  []*TableChange{nil, nil, nil},
+		},
+		{
+			name:     "version change",
+			tableId:  psi.SDT_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 0, 1}, {0, 1, 1}, {1, 1, 1}, {1, 0, 1}},
+			delivers: [][]interface{}{nil, {"v0s0", "v0s1"}, nil, {"v1s0", "v1s1"}},
+			changes:  []*TableChange{nil, nil, nil, {OldVersion: 0, NewVersion: 1}},
+		},
+		{
+			name:     "version change before completion",
+			tableId:  psi.SDT_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}},
+			delivers: [][]interface{}{nil, nil, {"v1s0", "v1s1"}},
+			changes:  []*TableChange{nil, nil, nil},
+		},
+		{
+			name:     "invalid section_number",
+			tableId:  psi.SDT_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 2, 1}},
+			delivers: [][]interface{}{nil},
+			changes:  []*TableChange{nil},
+		},
+		{
+			// NOTE version change of EIT is notified by the first section of new version.
+			name:     "each EIT section",
+			tableId:  psi.EIT_PF_ACTUAL_TABLE_ID,
+			pushes:   []cachePush{{0, 1, 1}, {0, 0, 1}, {1, 0, 1}, {1, 1, 1}},
+			delivers: [][]interface{}{{"v0s1"}, {"v0s0"}, {"v1s0"}, {"v1s1"}},
+			changes:  []*TableChange{nil, nil, {OldVersion: 0, NewVersion: 1}, nil},
+		},
+	}
+	for _, c := range cases {
+		cache := NewTableCache()
+		key := TableKey{Pid: 0x0011, TableId: c.tableId, TableIdExtension: 1}
+		for idx, push := range c.pushes {
+			header := psi.ExtendedHeader{
+				TableIdExtension:     1,
+				VersionNumber:        push.version,
+				CurrentNextIndicator: true,
+				SectionNumber:        push.sectionNumber,
+				LastSectionNumber:    push.lastNumber,
+			}
+			section := "v" + string('0'+rune(push.version)) + "s" + string('0'+rune(push.sectionNumber))
+			delivers, change := cache.Push(key, header, section)
+			if !reflect.DeepEqual(c.delivers[idx], delivers) {
+				t.Errorf("%s: push %d delivers %v, want %v", c.name, idx, delivers, c.delivers[idx])
+			}
+			if nil != c.changes[idx] {
+				c.changes[idx].Key = key
+			}
+			if !reflect.DeepEqual(c.changes[idx], change) {
+				t.Errorf("%s: push %d changes %+v, want %+v", c.name, idx, change, c.changes[idx])
+			}
+		}
+	}
+}
+
+func TestNewTableKey(t *testing.T) {
+	cases := []struct {
+		name    string
+		pid     uint
+		section []byte
+		key     TableKey
+	}{
+		{
+			name:    "PMT",
+			pid:     0x0100,
+			section: []byte{psi.PMT_TABLE_ID, 0xB0, 0x12, 0x00, 0x01, 0xC1, 0x00, 0x00, 0xE1, 0x01, 0xF0, 0x00},
+			key:     TableKey{Pid: 0x0100, TableId: psi.PMT_TABLE_ID, TableIdExtension: 1},
+		},
+		{
+			name:    "SDT other",
+			pid:     0x0011,
+			section: []byte{psi.SDT_OTHER_TABLE_ID, 0xF0, 0x12, 0x00, 0x02, 0xC1, 0x00, 0x00, 0x00, 0x04, 0xFF},
+			key:     TableKey{Pid: 0x0011, TableId: psi.SDT_OTHER_TABLE_ID, TableIdExtension: 2, OriginalNetworkId: 4},
+		},
+		{
+			name:    "EIT",
+			pid:     0x0012,
+			section: []byte{psi.EIT_PF_OTHER_TABLE_ID, 0xF0, 0x12, 0x00, 0x65, 0xC1, 0x00, 0x01, 0x00, 0x02, 0x00, 0x04, 0x01, 0x4F},
+			key:     TableKey{Pid: 0x0012, TableId: psi.EIT_PF_OTHER_TABLE_ID, TableIdExtension: 0x65, TransportStreamId: 2, OriginalNetworkId: 4},
+		},
+	}
+	for _, c := range cases {
+		header, err := psi.ParseExtendedHeader(c.section)
+		if nil != err {
+			t.Fatalf("%s: ParseExtendedHeader returns %v", c.name, err)
+		}
+		if key := NewTableKey(c.pid, c.section, header); c.key != key {
+			t.Errorf("%s: NewTableKey returns %+v, want %+v", c.name, key, c.key)
+		}
+	}
+}
+
+func TestTableCacheIsRepeated(t *testing.T) {
+	key := TableKey{Pid: 0x0011, TableId: psi.SDT_ACTUAL_TABLE_ID, TableIdExtension: 1}
+	header := psi.ExtendedHeader{TableIdExtension: 1, VersionNumber: 3, CurrentNextIndicator: true, SectionNumber: 0, LastSectionNumber: 1}
+
+	cases := []struct {
+		name     string
+		key      TableKey
+		version  byte
+		section  byte
+		repeated bool
+	}{
+		{"same section", key, 3, 0, true},
+		{"other section", key, 3, 1, false},
+		{"other version", key, 4, 0, false},
+		{"other original_network_id", TableKey{Pid: 0x0011, TableId: psi.SDT_ACTUAL_TABLE_ID, TableIdExtension: 1, OriginalNetworkId: 4}, 3, 0, false},
+		{"other PID", TableKey{Pid: 0x0012, TableId: psi.SDT_ACTUAL_TABLE_ID, TableIdExtension: 1}, 3, 0, false},
+	}
+	for _, c := range cases {
+		cache := NewTableCache()
+		cache.Push(key, header, "section")
+		h := header
+		h.VersionNumber = c.version
+		h.SectionNumber = c.section
+		if repeated := cache.IsRepeated(c.key, h); c.repeated != repeated {
+			t.Errorf("%s: IsRepeated returns %v, want %v", c.name, repeated, c.repeated)
+		}
+	}
+}
+
+func TestTableCacheRemovePid(t *testing.T) {
+	header := psi.ExtendedHeader{TableIdExtension: 1, VersionNumber: 0, CurrentNextIndicator: true}
+	keys := []TableKey{
+		{Pid: 0x0100, TableId: psi.PMT_TABLE_ID, TableIdExtension: 1},
+		{Pid: 0x0100, TableId: psi.PMT_TABLE_ID, TableIdExtension: 2},
+		{Pid: 0x0101, TableId: psi.PMT_TABLE_ID, TableIdExtension: 3},
+	}
+	cache := NewTableCache()
+	for _, key := range keys {
+		cache.Push(key, header, "section")
+	}
+	cache.RemovePid(0x0100)
+
+	for _, key := range keys {
+		repeated := cache.IsRepeated(key, header)
+		if (0x0100 == key.Pid) == repeated {
+			t.Errorf("table %+v is repeated %v after PID 0x0100 is removed", key, repeated)
+		}
+	}
+}
+
+func TestParserCurrentNextIndicator(t *testing.T) {
+	// PAT whose current_next_indicator is 0 is not applicable yet.
+	nextPat := newTestPat(1, 0x0200)
+	nextPat[5] &= 0xFE
+	copy(nextPat, newTestCrcSection(psi.PAT_TABLE_ID, nextPat[3:len(nextPat)-psi.CRC_LENGTH]))
+
+	stream := []byte{}
+	stream = append(stream, newTestSectionPacket(0x0000, 0, nextPat)...)
+	stream = append(stream, newTestSectionPacket(0x0000, 1, newTestPat(0, 0x0100))...)
+	stream = append(stream, newTestSectionPacket(0x0000, 2, newTestPat(0, 0x0100))...)
+
+	versions := []byte{}
+	parser := NewParser()
+	parser.Handler = Handler{
+		OnPAT: func(pid uint, pat *psi.PATField) {
+			versions = append(versions, pat.VersionNumber)
+		},
+	}
+	err := parser.ParseReader(context.Background(), bytes.NewReader(stream))
+	if nil != err {
+		t.Fatalf("ParseReader returns %v", err)
+	}
+	if !bytes.Equal([]byte{0}, versions) {
+		t.Errorf("PAT versions %v are delivered, want [0]", versions)
+	}
+	if _, ok := parser.tables[0x0200]; ok {
+		t.Errorf("PID of not applicable PAT is registered")
+	}
+}
@@ -16,6 +16,10 @@ type (
 		AdaptationFieldControl     byte
 		ContinuityCounter          byte
 
+		// Only M2TS(BDAV) packet has TP_extra_header.
+		CopyPermissionIndicator byte
+		ArrivalTimeStamp        uint32
+
 		Adaptation *AdaptationField
 		Payload    []byte
 	}
@@ -31,25 +35,86 @@ type (
 		TransportPrivateDataFlag          bool
 		AdaptationFieldExtensionFlag      bool
 
-		// T.B.D.
 		// Optionals
-		//PCR
-		//OPCR
-		//SpliceCountdown byte
-		//TransportPrivateDataLength byte
-		//TransportPrivateData	[]byte
-		//AdaptationExtension []byte
-		//StuffingBytes	byte
+		PCR                        PCR
+		OPCR                       PCR
+		SpliceCountdown            int8
+		TransportPrivateDataLength byte
+		TransportPrivateData       []byte
+		Extension                  *AdaptationExtension
+	}
+
+	// Program clock reference.
+	// Base is 90kHz unit (33bit), Extension is 27MHz unit (9bit).
+	PCR struct {
+		Base      uint64
+		reserved  byte
+		Extension uint
 	}
+
+	AdaptationExtension struct {
+		FieldLength        byte
+		LTWFlag            bool
+		PiecewiseRateFlag  bool
+		SeamlessSpliceFlag bool
+
+		// Optionals
+		LTWValidFlag  bool
+		LTWOffset     uint
+		PiecewiseRate uint
+		SpliceType    byte
+		DTSNextAU     uint64
+	}
+)
+
+const (
+	PACKET_SIZE      = 188
+	M2TS_PACKET_SIZE = 192 // TP_extra_header + packet
+	RS_PACKET_SIZE   = 204 // packet + Reed-Solomon parity
+
+	TP_EXTRA_HEADER_LENGTH = 4
+)
+
+const (
+	PCR_LENGTH             = 6
+	LTW_LENGTH             = 2
+	PIECEWISE_RATE_LENGTH  = 3
+	SEAMLESS_SPLICE_LENGTH = 5
+
+	// PCR frequency (27MHz)
+	PCR_FREQUENCY = 27000000
 )
 
-const PACKET_SIZE = 188
+// ParsePacket parses packet which has PACKET_SIZE, M2TS_PACKET_SIZE or RS_PACKET_SIZE.
+func ParsePacket(buffer []byte) (packet *Packet, err error) {
+	switch len(buffer) {
+	case PACKET_SIZE:
+		return ParseTsHeader(buffer)
+	case M2TS_PACKET_SIZE:
+		packet, err = ParseTsHeader(buffer[TP_EXTRA_HEADER_LENGTH:])
+		if nil != err {
+			return nil, err
+		}
+		packet.CopyPermissionIndicator = (buffer[0] & 0xC0) >> 6
+		packet.ArrivalTimeStamp = binary.BigEndian.Uint32([]byte{buffer[0] & 0x3F, buffer[1], buffer[2], buffer[3]})
+		return packet, nil
+	case RS_PACKET_SIZE:
+		// NOTE parity bytes are ignored.
+		return ParseTsHeader(buffer[:PACKET_SIZE])
+	}
+	err = fmt.Errorf("Invalid buffer size for packet. (%d passed)", len(buffer))
+	return nil, err
+}
 
 func ParseTsHeader(buffer []byte) (packet *Packet, err error) {
 	if PACKET_SIZE != len(buffer) {
 		err = fmt.Errorf("Invalid buffer size for packet. (%d passed but explain %d)", len(buffer), PACKET_SIZE)
 		return
 	}
+	if SYNC_BYTE != buffer[0] {
+		err = fmt.Errorf("Invalid sync byte 0x%02X. (expect 0x%02X)", buffer[0], SYNC_BYTE)
+		return
+	}
 
 	packet = &Packet{
 		Magic: buffer[0],
@@ -64,7 +129,10 @@ func ParseTsHeader(buffer []byte) (packet *Packet, err error) {
 
 	bufferHead := 4
 	if packet.HaveAdaptation() {
-		adaptation, readLength := parseAdaptationField(buffer[bufferHead:PACKET_SIZE])
+		adaptation, readLength, adaptationErr := parseAdaptationField(buffer[bufferHead:PACKET_SIZE])
+		if nil != adaptationErr {
+			return nil, adaptationErr
+		}
 		packet.Adaptation = adaptation
 		bufferHead += readLength
 	}
@@ -75,21 +143,144 @@ func ParseTsHeader(buffer []byte) (packet *Packet, err error) {
 	return packet, err
 }
 
-func parseAdaptationField(buffer []byte) (adaptation *AdaptationField, readLength int) {
+func parseAdaptationField(buffer []byte) (adaptation *AdaptationField, readLength int, err error) {
 	adaptation = &AdaptationField{
-		FieldLength:                       buffer[0],
-		DiscontinuityIndicator:            (buffer[1] & 0x80) > 0,
-		RandomAccessIndicato:              (buffer[1] & 0x40) > 0,
-		ElementaryStreamPriorityIndicator: (buffer[1] & 0x20) > 0,
-		PCRFlag:                      (buffer[1] & 0x10) > 0,
-		OPCRFlag:                     (buffer[1] & 0x08) > 0,
-		SplicingPointFlag:            (buffer[1] & 0x04) > 0,
-		TransportPrivateDataFlag:     (buffer[1] & 0x02) > 0,
-		AdaptationFieldExtensionFlag: (buffer[1] & 0x01) > 0,
-		// TODO implement
+		FieldLength: buffer[0],
 	}
 	readLength = int(adaptation.FieldLength) + 1
-	return adaptation, readLength
+	if len(buffer) < readLength {
+		err = fmt.Errorf("Invalid adaptation field length. (%d passed but remain %d)", adaptation.FieldLength, len(buffer)-1)
+		return nil, 0, err
+	}
+	if 0 == adaptation.FieldLength {
+		// Only one stuffing byte.
+		return adaptation, readLength, nil
+	}
+
+	field := buffer[1:readLength]
+	adaptation.DiscontinuityIndicator = (field[0] & 0x80) > 0
+	adaptation.RandomAccessIndicato = (field[0] & 0x40) > 0
+	adaptation.ElementaryStreamPriorityIndicator = (field[0] & 0x20) > 0
+	adaptation.PCRFlag = (field[0] & 0x10) > 0
+	adaptation.OPCRFlag = (field[0] & 0x08) > 0
+	adaptation.SplicingPointFlag = (field[0] & 0x04) > 0
+	adaptation.TransportPrivateDataFlag = (field[0] & 0x02) > 0
+	adaptation.AdaptationFieldExtensionFlag = (field[0] & 0x01) > 0
+
+	idx := 1
+	if adaptation.PCRFlag {
+		if len(field) < idx+PCR_LENGTH {
+			return nil, 0, fmt.Errorf("Adaptation field is too short for PCR.")
+		}
+		adaptation.PCR = parsePCR(field[idx : idx+PCR_LENGTH])
+		idx += PCR_LENGTH
+	}
+	if adaptation.OPCRFlag {
+		if len(field) < idx+PCR_LENGTH {
+			return nil, 0, fmt.Errorf("Adaptation field is too short for OPCR.")
+		}
+		adaptation.OPCR = parsePCR(field[idx : idx+PCR_LENGTH])
+		idx += PCR_LENGTH
+	}
+	if adaptation.SplicingPointFlag {
+		if len(field) < idx+1 {
+			return nil, 0, fmt.Errorf("Adaptation field is too short for splice countdown.")
+		}
+		adaptation.SpliceCountdown = int8(field[idx])
+		idx++
+	}
+	if adaptation.TransportPrivateDataFlag {
+		if len(field) < idx+1 {
+			return nil, 0, fmt.Errorf("Adaptation field is too short for transport private data.")
+		}
+		adaptation.TransportPrivateDataLength = field[idx]
+		idx++
+		tail := idx + int(adaptation.TransportPrivateDataLength)
+		if len(field) < tail {
+			return nil, 0, fmt.Errorf("Invalid transport private data length. (%d passed but remain %d)", adaptation.TransportPrivateDataLength, len(field)-idx)
+		}
+		adaptation.TransportPrivateData = field[idx:tail]
+		idx = tail
+	}
+	if adaptation.AdaptationFieldExtensionFlag {
+		extension, extensionErr := parseAdaptationExtension(field[idx:])
+		if nil != extensionErr {
+			return nil, 0, extensionErr
+		}
+		adaptation.Extension = extension
+	}
+	// NOTE remain bytes are stuffing bytes.
+
+	return adaptation, readLength, nil
+}
+
+func parseAdaptationExtension(buffer []byte) (extension *AdaptationExtension, err error) {
+	if len(buffer) < 2 {
+		return nil, fmt.Errorf("Adaptation field is too short for adaptation field extension.")
+	}
+	extension = &AdaptationExtension{
+		FieldLength:        buffer[0],
+		LTWFlag:            (buffer[1] & 0x80) > 0,
+		PiecewiseRateFlag:  (buffer[1] & 0x40) > 0,
+		SeamlessSpliceFlag: (buffer[1] & 0x20) > 0,
+	}
+	tail := int(extension.FieldLength) + 1
+	if len(buffer) < tail {
+		return nil, fmt.Errorf("Invalid adaptation field extension length. (%d passed but remain %d)", extension.FieldLength, len(buffer)-1)
+	}
+
+	field := buffer[:tail]
+	idx := 2
+	if extension.LTWFlag {
+		if len(field) < idx+LTW_LENGTH {
+			return nil, fmt.Errorf("Adaptation field extension is too short for LTW.")
+		}
+		extension.LTWValidFlag = (field[idx] & 0x80) > 0
+		extension.LTWOffset = uint(binary.BigEndian.Uint16([]byte{field[idx] & 0x7F, field[idx+1]}))
+		idx += LTW_LENGTH
+	}
+	if extension.PiecewiseRateFlag {
+		if len(field) < idx+PIECEWISE_RATE_LENGTH {
+			return nil, fmt.Errorf("Adaptation field extension is too short for piecewise rate.")
+		}
+		extension.PiecewiseRate = uint(binary.BigEndian.Uint32([]byte{0x00, field[idx] & 0x3F, field[idx+1], field[idx+2]}))
+		idx += PIECEWISE_RATE_LENGTH
+	}
+	if extension.SeamlessSpliceFlag {
+		if len(field) < idx+SEAMLESS_SPLICE_LENGTH {
+			return nil, fmt.Errorf("Adaptation field extension is too short for seamless splice.")
+		}
+		extension.SpliceType = (field[idx] & 0xF0) >> 4
+		extension.DTSNextAU = decodeTimestamp(field[idx : idx+SEAMLESS_SPLICE_LENGTH])
+	}
+	return extension, nil
+}
+
+func parsePCR(buffer []byte) PCR {
+	return PCR{
+		Base: uint64(buffer[0])<<25 |
+			uint64(buffer[1])<<17 |
+			uint64(buffer[2])<<9 |
+			uint64(buffer[3])<<1 |
+			uint64(buffer[4])>>7,
+		reserved:  (buffer[4] & 0x7E) >> 1,
+		Extension: uint(buffer[4]&0x01)<<8 | uint(buffer[5]),
+	}
+}
+
+// Decode 33bit timestamp which is divided by marker bits.
+// (used by DTS_next_AU, PTS and DTS)
+func decodeTimestamp(buffer []byte) uint64 {
+	return uint64(buffer[0]&0x0E)<<29 |
+		uint64(buffer[1])<<22 |
+		uint64(buffer[2]&0xFE)<<14 |
+		uint64(buffer[3])<<7 |
+		uint64(buffer[4])>>1
+}
+
+// Value returns PCR as 27MHz unit.
+func (pcr PCR) Value() uint64 {
+	return pcr.Base*300 + uint64(pcr.Extension)
 }
 
 func (p Packet) HaveAdaptation() bool {
@@ -0,0 +1,148 @@
+package mpeg2ts
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// Returns packet of PID 0x0100 which has adaptation field and payload.
+func newTestAdaptationField(adaptation []byte) []byte {
+	packet := []byte{SYNC_BYTE, 0x01, 0x00, 0x30, byte(len(adaptation))}
+	packet = append(packet, adaptation...)
+	return append(packet, bytes.Repeat([]byte{0xFF}, PACKET_SIZE-len(packet))...)
+}
+
+func TestParsePacketPCR(t *testing.T) {
+	cases := []struct {
+		name       string
+		adaptation []byte
+		pcr        PCR
+		opcr       PCR
+		value      uint64
+	}{
+		{
+			name:       "PCR",
+			adaptation: []byte{0x10, 0x12, 0x34, 0x56, 0x78, 0xFE, 0x2B},
+			pcr:        PCR{Base: 0x2468ACF1, reserved: 0x3F, Extension: 0x2B},
+			value:      0x2468ACF1*300 + 0x2B,
+		},
+		{
+			name:       "max PCR",
+			adaptation: []byte{0x10, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+			pcr:        PCR{Base: 0x1FFFFFFFF, reserved: 0x3F, Extension: 0x1FF},
+			value:      0x1FFFFFFFF*300 + 0x1FF,
+		},
+		{
+			name:       "extension only",
+			adaptation: []byte{0x10, 0x00, 0x00, 0x00, 0x00, 0x7F, 0x2A},
+			pcr:        PCR{Base: 0, reserved: 0x3F, Extension: 0x12A},
+			value:      0x12A,
+		},
+		{
+			name:       "PCR and OPCR",
+			adaptation: []byte{0x18, 0x00, 0x00, 0x00, 0x00, 0xFE, 0x01, 0x12, 0x34, 0x56, 0x78, 0xFE, 0x2B},
+			pcr:        PCR{Base: 1, reserved: 0x3F, Extension: 1},
+			opcr:       PCR{Base: 0x2468ACF1, reserved: 0x3F, Extension: 0x2B},
+			value:      301,
+		},
+	}
+	for _, c := range cases {
+		packet, err := ParsePacket(newTestAdaptationField(c.adaptation))
+		if nil != err {
+			t.Fatalf("%s: ParsePacket returns %v", c.name, err)
+		}
+		adaptation := packet.Adaptation
+		if !adaptation.PCRFlag || c.pcr != adaptation.PCR || c.opcr != adaptation.OPCR {
+			t.Errorf("%s: PCR is %+v and OPCR is %+v, want %+v and %+v", c.name, adaptation.PCR, adaptation.OPCR, c.pcr, c.opcr)
+		}
+		if value := adaptation.PCR.Value(); c.value != value {
+			t.Errorf("%s: Value returns %d, want %d", c.name, value, c.value)
+		}
+	}
+}
+
+func TestParsePacketAdaptationField(t *testing.T) {
+	cases := []struct {
+		name            string
+		adaptation      []byte
+		spliceCountdown int8
+		privateData     []byte
+		extension       *AdaptationExtension
+		err             bool
+	}{
+		{
+			name:            "positive splice_countdown",
+			adaptation:      []byte{0x04, 0x05},
+			spliceCountdown: 5,
+		},
+		{
+			name:            "negative splice_countdown",
+			adaptation:      []byte{0x04, 0xFE},
+			spliceCountdown: -2,
+		},
+		{
+			name:        "transport private data",
+			adaptation:  []byte{0x02, 0x03, 0xAA, 0xBB, 0xCC},
+			privateData: []byte{0xAA, 0xBB, 0xCC},
+		},
+		{
+			name:       "valid LTW",
+			adaptation: []byte{0x01, 0x03, 0x80, 0x92, 0x34},
+			extension:  &AdaptationExtension{FieldLength: 3, LTWFlag: true, LTWValidFlag: true, LTWOffset: 0x1234},
+		},
+		{
+			name:       "invalid LTW",
+			adaptation: []byte{0x01, 0x03, 0x80, 0x7F, 0xFF},
+			extension:  &AdaptationExtension{FieldLength: 3, LTWFlag: true, LTWOffset: 0x7FFF},
+		},
+		{
+			name:       "piecewise_rate",
+			adaptation: []byte{0x01, 0x04, 0x40, 0xFF, 0xFF, 0xFF},
+			extension:  &AdaptationExtension{FieldLength: 4, PiecewiseRateFlag: true, PiecewiseRate: 0x3FFFFF},
+		},
+		{
+			name:       "seamless splice",
+			adaptation: []byte{0x01, 0x06, 0x20, 0xA9, 0x8D, 0x15, 0xCF, 0x13},
+			extension:  &AdaptationExtension{FieldLength: 6, SeamlessSpliceFlag: true, SpliceType: 0x0A, DTSNextAU: 0x123456789},
+		},
+		{
+			name:       "all extension fields",
+			adaptation: []byte{0x01, 0x0B, 0xE0, 0x80, 0x01, 0x00, 0x00, 0x02, 0x11, 0x00, 0x01, 0x00, 0x03},
+			extension: &AdaptationExtension{
+				FieldLength: 11, LTWFlag: true, PiecewiseRateFlag: true, SeamlessSpliceFlag: true,
+				LTWValidFlag: true, LTWOffset: 1, PiecewiseRate: 2, SpliceType: 1, DTSNextAU: 1,
+			},
+		},
+		{
+			name:       "short PCR",
+			adaptation: []byte{0x10, 0x00, 0x00},
+			err:        true,
+		},
+		{
+			name:       "short extension",
+			adaptation: []byte{0x01, 0x06, 0x20, 0xA9, 0x8D},
+			err:        true,
+		},
+	}
+	for _, c := range cases {
+		packet, err := ParsePacket(newTestAdaptationField(c.adaptation))
+		if c.err != (nil != err) {
+			t.Errorf("%s: ParsePacket returns %v", c.name, err)
+			continue
+		}
+		if c.err {
+			continue
+		}
+		adaptation := packet.Adaptation
+		if c.spliceCountdown != adaptation.SpliceCountdown {
+			t.Errorf("%s: splice_countdown is %d, want %d", c.name, adaptation.SpliceCountdown, c.spliceCountdown)
+		}
+		if !bytes.Equal(c.privateData, adaptation.TransportPrivateData) {
+			t.Errorf("%s: transport private data is % X, want % X", c.name, adaptation.TransportPrivateData, c.privateData)
+		}
+		if !reflect.DeepEqual(c.extension, adaptation.Extension) {
+			t.Errorf("%s: extension is %+v, want %+v", c.name, adaptation.Extension, c.extension)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package mpeg2ts
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"mpeg2ts/psi"
+)
+
+type (
+	xmltvDocument struct {
+		XMLName           xml.Name         `xml:"tv"`
+		GeneratorInfoName string           `xml:"generator-info-name,attr"`
+		Channels          []xmltvChannel   `xml:"channel"`
+		Programmes        []xmltvProgramme `xml:"programme"`
+	}
+
+	xmltvChannel struct {
+		Id           string      `xml:"id,attr"`
+		DisplayNames []xmltvText `xml:"display-name"`
+	}
+
+	xmltvProgramme struct {
+		Start      string         `xml:"start,attr"`
+		Stop       string         `xml:"stop,attr,omitempty"`
+		Channel    string         `xml:"channel,attr"`
+		Titles     []xmltvText    `xml:"title"`
+		Descs      []xmltvText    `xml:"desc,omitempty"`
+		Categories []xmltvText    `xml:"category,omitempty"`
+		EpisodeNum []xmltvEpisode `xml:"episode-num,omitempty"`
+	}
+
+	xmltvText struct {
+		Lang  string `xml:"lang,attr,omitempty"`
+		Value string `xml:",chardata"`
+	}
+
+	xmltvEpisode struct {
+		System string `xml:"system,attr"`
+		Value  string `xml:",chardata"`
+	}
+)
+
+const (
+	XMLTV_GENERATOR   = "mpeg2ts"
+	XMLTV_TIME_FORMAT = "20060102150405 -0700"
+	XMLTV_DOCTYPE     = `<!DOCTYPE tv SYSTEM "xmltv.dtd">`
+)
+
+// Two letter language codes of XMLTV by ISO 639-2 code.
+var xmltvLanguages = map[string]string{
+	psi.LANGUAGE_JPN: "ja",
+	psi.LANGUAGE_ENG: "en",
+}
+
+// ChannelId returns id of channel in XMLTV. (original_network_id.transport_stream_id.service_id)
+func (key ServiceKey) ChannelId() string {
+	return fmt.Sprintf("%d.%d.%d", key.OriginalNetworkId, key.TransportStreamId, key.ServiceId)
+}
+
+// WriteXMLTV writes services and events of epg as XMLTV document.
+// Channels are services which have name in SDT, and programmes are their events.
+// Start and stop are formatted in time zone of decoded start_time.
+func WriteXMLTV(writer io.Writer, epg *EPG) error {
+	document := xmltvDocument{
+		GeneratorInfoName: XMLTV_GENERATOR,
+	}
+	for _, key := range epg.Services() {
+		name, ok := epg.ServiceName(key)
+		if !ok {
+			continue
+		}
+		channel := xmltvChannel{
+			Id:           key.ChannelId(),
+			DisplayNames: []xmltvText{{Value: name}},
+		}
+		document.Channels = append(document.Channels, channel)
+
+		for _, event := range epg.Events(key, time.Time{}, time.Time{}) {
+			programme, ok := newXmltvProgramme(channel.Id, event)
+			if ok {
+				document.Programmes = append(document.Programmes, programme)
+			}
+		}
+	}
+
+	if _, err := io.WriteString(writer, xml.Header+XMLTV_DOCTYPE+"\n"); nil != err {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); nil != err {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+// Convert event to programme. Event which has no short event descriptor is not converted.
+func newXmltvProgramme(channelId string, event psi.EITEvent) (xmltvProgramme, bool) {
+	ed, ok := event.EventDescriptor()
+	if !ok {
+		return xmltvProgramme{}, false
+	}
+	lang := xmltvLanguage(ed.LanguageCode)
+
+	programme := xmltvProgramme{
+		Start:   event.StartTime.Format(XMLTV_TIME_FORMAT),
+		Channel: channelId,
+		Titles:  []xmltvText{{Lang: lang, Value: ed.EventName}},
+	}
+	// NOTE stop is omitted when duration is undefined.
+	if endTime, ok := event.EndTime(); ok {
+		programme.Stop = endTime.Format(XMLTV_TIME_FORMAT)
+	}
+
+	descriptions := []string{}
+	if "" != ed.Text {
+		descriptions = append(descriptions, ed.Text)
+	}
+	for _, item := range event.ExtendEventItems() {
+		descriptions = append(descriptions, item.Name+"\n"+item.Text)
+	}
+	if 0 < len(descriptions) {
+		programme.Descs = []xmltvText{{Lang: lang, Value: strings.Join(descriptions, "\n\n")}}
+	}
+
+	for _, content := range event.Contents() {
+		programme.Categories = append(programme.Categories, xmltvCategories(content, ed.LanguageCode)...)
+	}
+
+	if sd, ok := event.SeriesDescriptor(); ok && 0 < sd.EpisodeNumber {
+		episode := fmt.Sprintf(".%d.", sd.EpisodeNumber-1)
+		if 0 < sd.LastEpisodeNumber {
+			episode = fmt.Sprintf(".%d/%d.", sd.EpisodeNumber-1, sd.LastEpisodeNumber)
+		}
+		programme.EpisodeNum = []xmltvEpisode{
+			{System: "xmltv_ns", Value: episode},
+			{System: "onscreen", Value: fmt.Sprintf("#%d", sd.EpisodeNumber)},
+		}
+	}
+	return programme, true
+}
+
+// Genre names of content. ARIB genre is used for Japanese event, and DVB genre is used for others.
+func xmltvCategories(content psi.Content, languageCode string) (categories []xmltvText) {
+	genres := map[string]psi.Genre{}
+	if psi.LANGUAGE_JPN == languageCode {
+		genres[xmltvLanguage(psi.LANGUAGE_JPN)] = content.AribGenre(psi.LANGUAGE_JPN)
+		genres[xmltvLanguage(psi.LANGUAGE_ENG)] = content.AribGenre(psi.LANGUAGE_ENG)
+	} else {
+		genres[xmltvLanguage(psi.LANGUAGE_ENG)] = content.DvbGenre()
+	}
+
+	for _, lang := range []string{xmltvLanguage(psi.LANGUAGE_JPN), xmltvLanguage(psi.LANGUAGE_ENG)} {
+		genre, ok := genres[lang]
+		if !ok {
+			continue
+		}
+		for _, name := range []string{genre.Large, genre.Middle} {
+			if "" != name {
+				categories = append(categories, xmltvText{Lang: lang, Value: name})
+			}
+		}
+	}
+	return categories
+}
+
+func xmltvLanguage(languageCode string) string {
+	lang, ok := xmltvLanguages[languageCode]
+	if !ok {
+		return languageCode
+	}
+	return lang
+}
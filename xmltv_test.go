@@ -0,0 +1,76 @@
+package mpeg2ts
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"mpeg2ts/psi"
+)
+
+// Returns EPG of testServiceKey which has name in SDT and events.
+func newTestXmltvEpg() *EPG {
+	epg := NewEPG()
+	epg.PushSdt(&psi.SDTField{
+		CurrentNextIndicator: true,
+		TransportStreamId:    testServiceKey.TransportStreamId,
+		OriginalNetworkId:    testServiceKey.OriginalNetworkId,
+		Services: []psi.SDTService{
+			{
+				ServiceId:   testServiceKey.ServiceId,
+				Descriptors: []interface{}{psi.ServiceDescriptor{ServiceType: 0x01, ServiceName: "Test TV"}},
+			},
+		},
+	})
+
+	news := newTestEvent(1, 10, 0, 30*time.Minute)
+	news.Descriptors = []interface{}{
+		psi.EventDescriptor{LanguageCode: psi.LANGUAGE_JPN, EventName: "News", Text: "Today's news"},
+		// 出演 and 山田太郎 in JIS X 0208
+		psi.ExtendEventDescriptor{Number: 0, LastNumber: 1, LanguageCode: psi.LANGUAGE_JPN, Articles: []psi.Article{
+			{Name: []byte{0x3D, 0x50, 0x31, 0x69}, NameDescriptor: []byte{0x3B, 0x33, 0x45, 0x44}},
+		}},
+		psi.ExtendEventDescriptor{Number: 1, LastNumber: 1, LanguageCode: psi.LANGUAGE_JPN, Articles: []psi.Article{
+			{NameDescriptor: []byte{0x42, 0x40, 0x4F, 0x3A}},
+		}},
+		psi.ContentDescriptor{Contents: []psi.Content{{ContentNibbleLevel1: 0x0, ContentNibbleLevel2: 0x1}}},
+	}
+	drama := newTestEvent(2, 10, 30, 0)
+	drama.Descriptors = []interface{}{
+		psi.EventDescriptor{LanguageCode: psi.LANGUAGE_ENG, EventName: "Drama"},
+		psi.SeriesDescriptor{EpisodeNumber: 3, LastEpisodeNumber: 12},
+	}
+	// Event which has no short event descriptor is not written.
+	unknown := newTestEvent(3, 11, 0, time.Hour)
+
+	epg.Push(newTestEit(eitSection{psi.EIT_PF_ACTUAL_TABLE_ID, 0, 0, 1, 1, psi.EIT_PF_ACTUAL_TABLE_ID}, news))
+	epg.Push(newTestEit(eitSection{psi.EIT_PF_ACTUAL_TABLE_ID, 0, 1, 1, 1, psi.EIT_PF_ACTUAL_TABLE_ID}, drama))
+	epg.Push(newTestEit(eitSection{psi.EIT_SCHEDULE_ACTUAL_TABLE_ID, 0, 0, 0, 0, psi.EIT_SCHEDULE_ACTUAL_TABLE_ID}, unknown))
+
+	// Service which has no name in SDT is not written.
+	epg.Push(&psi.EITField{
+		Common:            psi.Common{TableId: psi.EIT_PF_OTHER_TABLE_ID},
+		ServiceId:         0x0066,
+		NextIndicator:     true,
+		TransportStreamId: testServiceKey.TransportStreamId,
+		OriginalNetworkId: testServiceKey.OriginalNetworkId,
+		Events:            []psi.EITEvent{news},
+	})
+	return epg
+}
+
+func TestWriteXMLTV(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := WriteXMLTV(buffer, newTestXmltvEpg())
+	if nil != err {
+		t.Fatalf("WriteXMLTV returns %v", err)
+	}
+	golden, err := ioutil.ReadFile("testdata/epg.xmltv")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(golden, buffer.Bytes()) {
+		t.Errorf("WriteXMLTV writes\n%s\nwant\n%s", buffer.String(), string(golden))
+	}
+}